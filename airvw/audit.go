@@ -0,0 +1,107 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// modelProvider 模型服务提供方
+const modelProvider = "dashscope"
+
+// 模型调用用途
+const (
+	AuditPurposeReview = "review" // 评审
+	AuditPurposeVerify = "verify" // 复核
+)
+
+// auditRunID 本次运行的标识，同一次评审的多次模型调用共用
+var auditRunID = fmt.Sprintf("%s-%d", time.Now().Format("20060102T150405"), os.Getpid())
+
+// redactedPattern 匹配prompt中的脱敏占位符
+var redactedPattern = regexp.MustCompile(`\[REDACTED:([^\]]+)\]`)
+
+// AuditRecord 审计日志记录：一次模型调用发送的内容
+type AuditRecord struct {
+	Time         string         `json:"time"`                 // 调用时间
+	RunID        string         `json:"run_id"`               // 运行标识
+	Provider     string         `json:"provider"`             // 模型服务提供方
+	Endpoint     string         `json:"endpoint"`             // 调用地址
+	Model        string         `json:"model"`                // 模型名称
+	Purpose      string         `json:"purpose"`              // 调用用途：review/verify
+	RepoID       int            `json:"repo_id,omitempty"`    // 仓库ID
+	MRID         int            `json:"mr_id,omitempty"`      // MR的ID
+	CommitID     string         `json:"commit_id,omitempty"`  // Commit hash
+	Files        []AuditFile    `json:"files"`                // 发送的文件及字节数
+	Excluded     []string       `json:"excluded,omitempty"`   // 按配置未发送的文件
+	Redactions   map[string]int `json:"redactions,omitempty"` // 各脱敏规则的命中次数
+	PromptBytes  int            `json:"prompt_bytes"`         // prompt总字节数
+	PromptSHA256 string         `json:"prompt_sha256"`        // prompt内容摘要，可与调试日志核对
+}
+
+// AuditFile 发送给模型的单个文件
+type AuditFile struct {
+	Path  string `json:"path"`  // 文件路径
+	Bytes int    `json:"bytes"` // 发送的字节数（脱敏后的diff和规则检查结果）
+}
+
+// auditModelCall 调用模型前将本次发送的内容追加到审计日志（JSON Lines）；未配置审计日志时不记录。
+// 写入失败时返回错误，调用方不应继续发送
+func auditModelCall(config Config, record AuditRecord, prompt string) error {
+	if config.AuditLog == "" {
+		return nil
+	}
+	record.Time = time.Now().Format(time.RFC3339)
+	record.RunID = auditRunID
+	record.Provider = modelProvider
+	record.Endpoint = dashScopeEndpoint
+	if record.Model == "" {
+		record.Model = defaultModel
+	}
+	record.RepoID = config.RepoID
+	record.MRID = config.MRID
+	record.CommitID = config.CommitID
+	for _, matches := range redactedPattern.FindAllStringSubmatch(prompt, -1) {
+		if record.Redactions == nil {
+			record.Redactions = make(map[string]int)
+		}
+		record.Redactions[matches[1]]++
+	}
+	record.PromptBytes = len(prompt)
+	sum := sha256.Sum256([]byte(prompt))
+	record.PromptSHA256 = hex.EncodeToString(sum[:])
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("生成审计记录失败：%w", err)
+	}
+	if dir := filepath.Dir(config.AuditLog); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("创建审计日志目录失败：%w", err)
+		}
+	}
+	f, err := os.OpenFile(config.AuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("打开审计日志%s失败：%w", config.AuditLog, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("写入审计日志%s失败：%w", config.AuditLog, err)
+	}
+	logDebug("📝【auditModelCall】已记录审计日志：%s，%d个文件，%d字节\n", record.Purpose, len(record.Files), record.PromptBytes)
+	return nil
+}
+
+// auditFiles prompt中各文件发送的字节数
+func auditFiles(files []PromptFile) []AuditFile {
+	audit := make([]AuditFile, 0, len(files))
+	for _, f := range files {
+		audit = append(audit, AuditFile{Path: f.Path, Bytes: len(f.Diff) + len(f.Lint)})
+	}
+	return audit
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// defaultBaselineFile 未指定--baseline时自动加载的基线文件
+const defaultBaselineFile = ".airvw-baseline.json"
+
+// baselineVersion 基线文件格式版本
+const baselineVersion = 1
+
+// Baseline 基线文件：记录已接受的存量问题指纹，命中基线的问题标记为已知且不参与卡点
+type Baseline struct {
+	Version   int             `json:"version"`
+	CreatedAt string          `json:"created_at"`
+	Entries   []BaselineEntry `json:"entries"`
+
+	index map[string]bool
+}
+
+// BaselineEntry 单个存量问题
+type BaselineEntry struct {
+	Fingerprint string `json:"fingerprint"`       // 指纹：文件+规范化代码片段+规则/分类
+	File        string `json:"file"`              // 文件路径
+	Rule        string `json:"rule,omitempty"`    // 规则ID，没有规则ID时为CWE编号
+	Level       string `json:"level,omitempty"`   // 创建基线时的问题等级，仅供阅读，不参与指纹
+	Snippet     string `json:"snippet,omitempty"` // 规范化后的代码片段
+	Issue       string `json:"issue,omitempty"`   // 创建基线时的问题描述，仅供阅读
+}
+
+// loadBaseline 加载基线文件；未显式指定且默认文件不存在时返回空基线
+func loadBaseline(path string) (*Baseline, error) {
+	baseline := &Baseline{index: make(map[string]bool)}
+	explicit := path != ""
+	if !explicit {
+		path = defaultBaselineFile
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return baseline, nil
+		}
+		return nil, fmt.Errorf("读取基线文件%s失败：%w", path, err)
+	}
+	if err := json.Unmarshal(data, baseline); err != nil {
+		return nil, fmt.Errorf("解析基线文件%s失败：%w", path, err)
+	}
+	for _, entry := range baseline.Entries {
+		baseline.index[entry.Fingerprint] = true
+	}
+	logDebug("✅【loadBaseline】已加载基线文件%s，共%d个存量问题\n", path, len(baseline.Entries))
+	return baseline, nil
+}
+
+// newBaseline 根据当前问题创建基线，相同指纹只记录一次
+func newBaseline(issues []BlockIssue, source *sourceReader) *Baseline {
+	baseline := &Baseline{
+		Version:   baselineVersion,
+		CreatedAt: time.Now().Format(time.RFC3339),
+		index:     make(map[string]bool),
+	}
+	for _, issue := range issues {
+		if issue.File == "unknown" {
+			continue
+		}
+		entry := newBaselineEntry(issue, source)
+		if baseline.index[entry.Fingerprint] {
+			continue
+		}
+		baseline.index[entry.Fingerprint] = true
+		baseline.Entries = append(baseline.Entries, entry)
+	}
+	sort.Slice(baseline.Entries, func(i, j int) bool {
+		if baseline.Entries[i].File != baseline.Entries[j].File {
+			return baseline.Entries[i].File < baseline.Entries[j].File
+		}
+		return baseline.Entries[i].Fingerprint < baseline.Entries[j].Fingerprint
+	})
+	return baseline
+}
+
+// Save 写入基线文件
+func (b *Baseline) Save(path string) error {
+	jsonData, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSON格式化失败：%w", err)
+	}
+	if err := os.WriteFile(path, append(jsonData, '\n'), 0644); err != nil {
+		return fmt.Errorf("写入基线文件%s失败：%w", path, err)
+	}
+	return nil
+}
+
+// Contains 判断问题是否命中基线
+func (b *Baseline) Contains(issue BlockIssue, source *sourceReader) bool {
+	if b == nil || len(b.index) == 0 || issue.File == "unknown" {
+		return false
+	}
+	return b.index[newBaselineEntry(issue, source).Fingerprint]
+}
+
+// newBaselineEntry 计算问题指纹：文件+规范化代码片段+规则ID（没有时依次为CWE编号、规范化的问题描述）。不包含行号，代码上下移动后仍能命中；
+// 不包含等级，模型给出的等级变化后仍能命中；同一行上的其他问题不会命中；读取不到代码时以问题描述代替代码片段
+func newBaselineEntry(issue BlockIssue, source *sourceReader) BaselineEntry {
+	// 按卡点策略提升过等级的问题记录原等级
+	entry := BaselineEntry{
+		File:  issue.File,
+		Rule:  firstNonEmpty(issue.RuleID, issue.CWE),
+		Level: firstNonEmpty(issue.PromotedFrom, issue.Level),
+		Issue: issue.Issue,
+	}
+	if lineNo, err := strconv.Atoi(issue.Line); err == nil {
+		entry.Snippet = normalizeSnippet(source.Line(issue.File, lineNo))
+	}
+	content := entry.Snippet
+	if content == "" {
+		content = normalizeSnippet(issue.Issue)
+	}
+	category := entry.Rule
+	if category == "" {
+		category = normalizeIssueText(issue.Issue)
+	}
+	sum := sha256.Sum256([]byte(entry.File + "\x00" + content + "\x00" + category))
+	entry.Fingerprint = hex.EncodeToString(sum[:])[:16]
+	return entry
+}
+
+// normalizeSnippet 规范化代码片段：去掉首尾空白并合并连续空白，忽略缩进和格式化差异
+func normalizeSnippet(snippet string) string {
+	return strings.Join(strings.Fields(snippet), " ")
+}
+
+// normalizeIssueText 规范化问题描述：只保留字母和数字并转为小写，忽略空白、标点和大小写差异
+func normalizeIssueText(issue string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, issue)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestBaselineContains 测试基线指纹：代码移动行号、调整缩进或等级变化后仍命中，代码或问题变化后不命中
+func TestBaselineContains(t *testing.T) {
+	root := t.TempDir()
+	write := func(content string) {
+		if err := os.WriteFile(filepath.Join(root, "a.go"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("package a\n\nfunc A() {\n\tpanic(\"x\")\n}\n")
+	known := BlockIssue{Level: LevelBlock, File: "a.go", Line: "4", Issue: "使用panic"}
+	baseline := newBaseline([]BlockIssue{known, known}, newSourceReader(root, nil))
+	if len(baseline.Entries) != 1 {
+		t.Fatalf("基线条目数 = %d，期望1", len(baseline.Entries))
+	}
+	path := filepath.Join(root, defaultBaselineFile)
+	if err := baseline.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 上方新增代码、缩进变化后，同一行代码的问题仍为存量问题
+	write("package a\n\nimport \"log\"\n\nfunc A() {\n\tlog.Println(1)\n        panic(\"x\")\n}\n")
+	source := newSourceReader(root, nil)
+	tests := []struct {
+		issue BlockIssue
+		want  bool
+	}{
+		{BlockIssue{Level: LevelBlock, File: "a.go", Line: "7", Issue: "使用 Panic。"}, true},
+		{BlockIssue{Level: LevelHigh, File: "a.go", Line: "7", Issue: "使用panic"}, true},
+		{BlockIssue{Level: LevelBlock, File: "a.go", Line: "7", Issue: "panic信息中缺少上下文"}, false},
+		{BlockIssue{Level: LevelHigh, File: "a.go", Line: "7", Issue: "使用panic", PromotedFrom: LevelMedium}, true},
+		{BlockIssue{Level: LevelBlock, File: "a.go", Line: "7", Issue: "使用panic", RuleID: "GO-001"}, false},
+		{BlockIssue{Level: LevelBlock, File: "a.go", Line: "7", Issue: "使用panic", CWE: "CWE-248"}, false},
+		{BlockIssue{Level: LevelBlock, File: "a.go", Line: "6", Issue: "使用panic"}, false},
+		{BlockIssue{Level: LevelBlock, File: "b.go", Line: "7", Issue: "使用panic"}, false},
+	}
+	for _, tt := range tests {
+		if got := loaded.Contains(tt.issue, source); got != tt.want {
+			t.Errorf("Contains(%+v) = %v，期望%v", tt.issue, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 重命名/删除文件的变更类型
+const (
+	ChangeRenamed = "renamed" // 重命名
+	ChangeRemoved = "removed" // 删除
+)
+
+// maxRemovedSymbols 每个删除文件最多提供给模型的声明数
+const maxRemovedSymbols = 20
+
+// FileChange 重命名或删除的文件，提供给模型用于检查其他变更文件中失效的引用
+type FileChange struct {
+	Status  string   // renamed/removed
+	Path    string   // 重命名后的路径，删除时为原路径
+	OldPath string   // 重命名前的路径
+	Symbols []string // 删除文件中移除的声明
+}
+
+// declarationPatterns 常见语言的声明语句，用于从删除的代码中提取被移除的类型/函数
+var declarationPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^func\s+(?:\([^)]*\)\s*)?([A-Za-z_]\w*)`), // Go函数/方法
+	regexp.MustCompile(`^type\s+([A-Za-z_]\w*)`),                  // Go类型
+	regexp.MustCompile(`^\s*(?:(?:public|protected|internal|open|abstract|final|static|sealed|data)\s+)*(?:class|interface|enum|struct|object|protocol)\s+([A-Za-z_]\w*)`),  // 类/接口
+	regexp.MustCompile(`^\s*(?:public|protected)\s+(?:(?:static|final|abstract|synchronized)\s+)*[\w<>\[\],.? ]+?\s+([A-Za-z_]\w*)\s*\(`),                                   // Java方法
+	regexp.MustCompile(`^\s*(?:(?:public|open|internal|override|suspend)\s+)*fun\s+(?:<[^>]*>\s*)?(?:[\w.]+\.)?([A-Za-z_]\w*)`),                                             // Kotlin函数
+	regexp.MustCompile(`^\s*(?:(?:public|open)\s+)+(?:static\s+)?func\s+([A-Za-z_]\w*)`),                                                                                    // Swift函数
+	regexp.MustCompile(`^(?:async\s+)?def\s+([A-Za-z]\w*)|^class\s+([A-Za-z]\w*)`),                                                                                          // Python顶层函数/类（忽略下划线开头的私有声明）
+	regexp.MustCompile(`^export\s+(?:default\s+)?(?:declare\s+)?(?:abstract\s+)?(?:async\s+)?(?:function\*?|class|const|let|var|interface|type|enum)\s+([A-Za-z_$][\w$]*)`), // JS/TS导出
+}
+
+// goReceiverPattern Go方法的接收者类型
+var goReceiverPattern = regexp.MustCompile(`^func\s+\(\s*(?:\w+\s+)?\*?\s*([A-Za-z_]\w*)`)
+
+// collectFileChanges 收集当前语言的重命名/删除文件：重命名文件记录新旧路径，删除文件提取被移除的声明
+func collectFileChanges(diffItems []DiffItem, process ReviewProcess) []FileChange {
+	var changes []FileChange
+	for _, item := range diffItems {
+		switch {
+		case item.Binary:
+			continue
+		case item.DeletedFile:
+			path := firstNonEmpty(item.OldPath, item.NewPath)
+			if !isLanguageFile(process, path) {
+				continue
+			}
+			change := FileChange{Status: ChangeRemoved, Path: path, Symbols: removedSymbols(path, item.Diff)}
+			logDebug("🗑️【collectFileChanges】删除文件：%s，移除的声明：%v\n", path, change.Symbols)
+			changes = append(changes, change)
+		case item.RenamedFile && item.OldPath != "" && item.OldPath != item.NewPath:
+			if !isLanguageFile(process, item.NewPath) && !isLanguageFile(process, item.OldPath) {
+				continue
+			}
+			logDebug("🚚【collectFileChanges】重命名文件：%s → %s\n", item.OldPath, item.NewPath)
+			changes = append(changes, FileChange{Status: ChangeRenamed, Path: item.NewPath, OldPath: item.OldPath})
+		}
+	}
+	return changes
+}
+
+// isLanguageFile 判断文件是否属于当前评审语言，复用各语言FilterFiles的后缀判断
+func isLanguageFile(process ReviewProcess, path string) bool {
+	return len(process.FilterFiles([]DiffItem{{NewPath: path, Diff: "-"}})) > 0
+}
+
+// removedSymbols 从diff的删除行中提取被移除的公开声明名称，按出现顺序去重；私有声明不会被其他文件引用，不提供给模型
+func removedSymbols(file, diff string) []string {
+	var symbols []string
+	seen := make(map[string]bool)
+	ext := strings.ToLower(path.Ext(file))
+	for _, line := range parseDiffLines(diff) {
+		if line.Kind != '-' {
+			continue
+		}
+		name := declarationName(line.Text)
+		if name == "" || seen[name] || !isExportedDeclaration(ext, line.Text, name) {
+			continue
+		}
+		seen[name] = true
+		symbols = append(symbols, name)
+		if len(symbols) >= maxRemovedSymbols {
+			break
+		}
+	}
+	return symbols
+}
+
+// isExportedDeclaration 按语言的导出规则判断声明是否为公开API：Go以大写字母开头（方法还要求接收者类型导出），
+// Java/C#/Swift需要public、protected或open修饰，Kotlin默认公开但排除internal；其他语言的声明模式只匹配公开声明
+func isExportedDeclaration(ext, line, name string) bool {
+	switch ext {
+	case ".go":
+		if matches := goReceiverPattern.FindStringSubmatch(line); matches != nil && !isUpperInitial(matches[1]) {
+			return false
+		}
+		return isUpperInitial(name)
+	case ".java", ".cs", ".swift":
+		return hasModifier(line, "public", "protected", "open")
+	case ".kt", ".kts":
+		return !hasModifier(line, "internal")
+	}
+	return true
+}
+
+// isUpperInitial 判断名称是否以大写字母开头
+func isUpperInitial(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
+
+// hasModifier 判断声明语句是否带有任一修饰符
+func hasModifier(line string, modifiers ...string) bool {
+	for _, field := range strings.Fields(line) {
+		for _, modifier := range modifiers {
+			if field == modifier {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// declarationName 返回声明语句中的名称，不是声明时返回空串
+func declarationName(line string) string {
+	for _, pattern := range declarationPatterns {
+		matches := pattern.FindStringSubmatch(line)
+		if matches == nil {
+			continue
+		}
+		for _, name := range matches[1:] {
+			if name != "" {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// renamedFrom 查找文件重命名前的路径，未重命名时返回空串
+func renamedFrom(changes []FileChange, path string) string {
+	for _, change := range changes {
+		if change.Status == ChangeRenamed && change.Path == path {
+			return change.OldPath
+		}
+	}
+	return ""
+}
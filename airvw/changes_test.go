@@ -0,0 +1,90 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestCollectFileChanges 测试重命名/删除文件的收集、语言过滤以及删除的公开声明的提取
+func TestCollectFileChanges(t *testing.T) {
+	diffItems := parseGitDiff(`diff --git a/pkg/old.go b/pkg/new.go
+rename from pkg/old.go
+rename to pkg/new.go
+--- a/pkg/old.go
++++ b/pkg/new.go
+@@ -1,2 +1,2 @@
+ package pkg
+-func A() {}
++func A() int { return 1 }
+diff --git a/pkg/gone.go b/pkg/gone.go
+deleted file mode 100644
+--- a/pkg/gone.go
++++ /dev/null
+@@ -1,8 +0,0 @@
+-package pkg
+-
+-type Client struct{}
+-type options struct{}
+-
+-func (c *Client) Do() error { return nil }
+-func (o *options) Apply() {}
+-func helper() {}
+diff --git a/docs/a.md b/docs/a.md
+deleted file mode 100644
+--- a/docs/a.md
++++ /dev/null
+@@ -1 +0,0 @@
+-x
+`)
+	process := GetReviewProcess("go")
+
+	want := []FileChange{
+		{Status: ChangeRenamed, Path: "pkg/new.go", OldPath: "pkg/old.go"},
+		{Status: ChangeRemoved, Path: "pkg/gone.go", Symbols: []string{"Client", "Do"}},
+	}
+	if got := collectFileChanges(diffItems, process); !reflect.DeepEqual(got, want) {
+		t.Errorf("collectFileChanges() = %+v，期望%+v", got, want)
+	}
+	if diffFiles := process.FilterFiles(diffItems); len(diffFiles) != 1 || diffFiles["pkg/new.go"] == "" {
+		t.Errorf("FilterFiles() = %v，期望只包含重命名且有改动的pkg/new.go", diffFiles)
+	}
+}
+
+// TestDeclarationName 测试各语言声明名称的提取
+func TestDeclarationName(t *testing.T) {
+	cases := map[string]string{
+		"public class OrderService {":                       "OrderService",
+		"    public static List<Order> listOrders(int n) {": "listOrders",
+		"fun String.toSlug(): String":                       "toSlug",
+		"public func fetch() async throws":                  "fetch",
+		"def create_user(name):":                            "create_user",
+		"def _private():":                                   "",
+		"export default async function handler(req) {":      "handler",
+		"export interface Props {":                          "Props",
+		"return nil":                                        "",
+	}
+	for line, want := range cases {
+		if got := declarationName(line); got != want {
+			t.Errorf("declarationName(%q) = %q，期望%q", line, got, want)
+		}
+	}
+}
+
+// TestRemovedSymbols 测试按语言的导出规则过滤删除的私有声明
+func TestRemovedSymbols(t *testing.T) {
+	cases := []struct {
+		file string
+		diff string
+		want []string
+	}{
+		{"src/main/java/Api.java", "@@ -1,4 +0,0 @@\n-public class Api {\n-    public void call() {}\n-    void internalCall() {}\n-}\n-class Helper {}\n", []string{"Api", "call"}},
+		{"app/Repo.kt", "@@ -1,3 +0,0 @@\n-class Repo\n-internal class Cache\n-fun load() {}\n-internal fun warm() {}\n", []string{"Repo", "load"}},
+		{"Sources/Client.swift", "@@ -1,3 +0,0 @@\n-public class Client {}\n-class Session {}\n-public func fetch() {}\n", []string{"Client", "fetch"}},
+		{"Services/Order.cs", "@@ -1,2 +0,0 @@\n-public class Order {}\n-internal class OrderCache {}\n", []string{"Order"}},
+	}
+	for _, c := range cases {
+		if got := removedSymbols(c.file, c.diff); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("removedSymbols(%s) = %v，期望%v", c.file, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// runSubcommand 执行子命令，返回是否命中子命令及退出码
+func runSubcommand(args []string) (bool, int) {
+	if len(args) < 2 {
+		return false, 0
+	}
+	switch {
+	case args[0] == "prompt" && args[1] == "render":
+		return true, runPromptRender(args[2:])
+	case args[0] == "baseline" && args[1] == "create":
+		return true, runBaselineCreate(args[2:])
+	}
+	return false, 0
+}
+
+// runPromptRender airvw prompt render：打印指定diff实际发送给模型的prompt
+func runPromptRender(args []string) int {
+	fs := flag.NewFlagSet("prompt render", flag.ContinueOnError)
+	language := fs.String("language", "golang", "评审语言："+strings.Join(languageNames(), "/")+"，或配置文件languages中声明的语言")
+	diffFile := fs.String("diff-file", "-", "git diff输出文件路径，-表示从标准输入读取")
+	configFile := fs.String("config", "", "配置文件路径（可选，默认读取当前目录下的.airvw.json）")
+	promptDir := fs.String("prompt-dir", "", "prompt模板覆盖目录（可选）")
+	locale := fs.String("locale", "", "输出语言：zh/en（可选）")
+	withLint := fs.Bool("with-lint", false, "是否执行静态检查并将结果渲染进prompt，默认false")
+	profile := fs.String("profile", ProfileDefault, "评审模式：default/security")
+	fs.BoolVar(&debugMode, "debug", false, "是否开启调试模式，默认false")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "用法：airvw prompt render [参数]\n  git diff origin/main | airvw prompt render --language golang")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	fileConfig, err := loadFileConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌【prompt render】加载配置文件失败：%s\n", err)
+		return 1
+	}
+
+	var diffText []byte
+	if *diffFile == "-" {
+		diffText, err = io.ReadAll(os.Stdin)
+	} else {
+		diffText, err = os.ReadFile(*diffFile)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌【prompt render】读取diff失败：%s\n", err)
+		return 1
+	}
+
+	if err := validateProfile(*profile); err != nil {
+		fmt.Fprintf(os.Stderr, "❌【prompt render】%s\n", err)
+		return 2
+	}
+	config := Config{Language: *language, Locale: *locale, PromptDir: *promptDir, Profile: *profile, File: fileConfig}
+	applyPromptConfig(&config)
+	if err := applyRedactionConfig(&config); err != nil {
+		fmt.Fprintf(os.Stderr, "❌【prompt render】%s\n", err)
+		return 1
+	}
+	applyDependencyConfig(&config)
+	registerLanguages(config.File.Languages)
+
+	pathFilter, err := newPathFilter(config.File.Filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌【prompt render】%s\n", err)
+		return 1
+	}
+	process := applyProfile(GetReviewProcess(config.Language), config.Profile)
+	diffItems := pathFilter.Apply(parseGitDiff(string(diffText)))
+	diffFiles := process.FilterFiles(diffItems)
+	if len(diffFiles) == 0 {
+		fmt.Fprintf(os.Stderr, "ℹ️【prompt render】diff中没有需评审的%s文件\n", process.GetFileExtension())
+		return 0
+	}
+	if excluded := redactor.ExcludedFiles(diffFiles); len(excluded) > 0 {
+		fmt.Fprintf(os.Stderr, "ℹ️【prompt render】以下文件按redaction.exclude_paths配置不发送给模型：%s\n", strings.Join(excluded, ", "))
+	}
+	lintResults := make(map[string]string)
+	if *withLint {
+		lintResults = process.RunLint(".", diffFiles)
+	}
+
+	prompt, err := prompts.Render(process.GetPromptName(), PromptInput{
+		DiffFiles:   diffFiles,
+		Changes:     collectFileChanges(diffItems, process),
+		LintResults: lintResults,
+		Rules:       applicableRules(config.File.Rules, config.Language, diffFiles),
+		Locale:      config.Locale,
+		Language:    languageDisplayName(config.Language),
+		Dimensions:  languageDimensions(process),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌【prompt render】%s\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "ℹ️【prompt render】prompt版本：%s\n", prompts.Version(process.GetPromptName()))
+	fmt.Print(prompt)
+	return 0
+}
+
+// runBaselineCreate airvw baseline create：根据评审报告（--report-file输出）中的问题生成基线文件
+func runBaselineCreate(args []string) int {
+	fs := flag.NewFlagSet("baseline create", flag.ContinueOnError)
+	reportFile := fs.String("report", "", "评审报告JSON文件路径（airvw --report-file的输出，必填）")
+	output := fs.String("output", defaultBaselineFile, "基线文件输出路径")
+	root := fs.String("root", ".", "仓库根目录，用于读取问题所在行的代码")
+	fs.BoolVar(&debugMode, "debug", false, "是否开启调试模式，默认false")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "用法：airvw baseline create --report airvw-report.json [参数]\n  建议以--advisory模式执行一次评审并输出报告，使报告包含全部等级的问题")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *reportFile == "" {
+		fs.Usage()
+		return 2
+	}
+
+	data, err := os.ReadFile(*reportFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌【baseline create】读取评审报告失败：%s\n", err)
+		return 1
+	}
+	var result ReviewResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		fmt.Fprintf(os.Stderr, "❌【baseline create】解析评审报告%s失败：%s\n", *reportFile, err)
+		return 1
+	}
+
+	baseline := newBaseline(result.BlockIssues, newSourceReader(*root, nil))
+	if err := baseline.Save(*output); err != nil {
+		fmt.Fprintf(os.Stderr, "❌【baseline create】%s\n", err)
+		return 1
+	}
+	fmt.Printf("✅【baseline create】已将%d个问题写入基线文件%s\n", len(baseline.Entries), *output)
+	return 0
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// defaultConfigFile 未指定--config时自动加载的配置文件
+const defaultConfigFile = ".airvw.json"
+
+// FileConfig 配置文件结构体（--config指定的JSON文件，命令行参数无法表达的配置放在这里）
+type FileConfig struct {
+	DingTalk     DingTalkConfig   `json:"dingtalk"`     // 钉钉通知配置
+	Notifiers    []NotifierConfig `json:"notifiers"`    // 通知渠道列表，可同时配置多个
+	Locale       string           `json:"locale"`       // 评论/通知语言区域：zh/en
+	Templates    TemplateConfig   `json:"templates"`    // 评论/通知模板覆盖配置
+	Rules        []ReviewRule     `json:"rules"`        // 团队自定义评审规则
+	PromptDir    string           `json:"prompt_dir"`   // prompt模板覆盖目录
+	Filter       PathFilterConfig `json:"filter"`       // 评审路径过滤配置
+	Policy       PolicyConfig     `json:"policy"`       // 卡点策略配置
+	Languages    []LanguageSpec   `json:"languages"`    // 自定义语言，与内置语言同名时覆盖内置语言
+	Redaction    RedactionConfig  `json:"redaction"`    // 发送给模型内容的脱敏与审计配置
+	Dependencies DependencyConfig `json:"dependencies"` // 依赖变更评审配置：离线漏洞库和许可证允许列表
+	TestGap      TestGapConfig    `json:"test_gap"`     // 测试缺口检查配置
+}
+
+// DingTalkConfig 钉钉通知配置
+type DingTalkConfig struct {
+	// Users 提交人邮箱/姓名 -> 钉钉账号映射，用于@MR作者和评审人
+	Users map[string]DingTalkUser `json:"users"`
+	// Reviewers 固定需要@的评审人（邮箱/姓名，需在users中存在映射）
+	Reviewers []string `json:"reviewers"`
+	// ProtectedBranches 受保护分支，仅当目标分支命中且存在block级问题时才@所有人
+	ProtectedBranches []string `json:"protected_branches"`
+}
+
+// DingTalkUser 钉钉账号，手机号和userId二选一即可
+type DingTalkUser struct {
+	Mobile string `json:"mobile"`  // 钉钉绑定的手机号
+	UserID string `json:"user_id"` // 钉钉userId
+}
+
+// loadFileConfig 加载配置文件；未显式指定且默认文件不存在时返回空配置
+func loadFileConfig(path string) (FileConfig, error) {
+	var fileConfig FileConfig
+	explicit := path != ""
+	if !explicit {
+		path = defaultConfigFile
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			logDebug("ℹ️【loadFileConfig】未找到配置文件%s，使用默认配置\n", path)
+			return fileConfig, nil
+		}
+		return fileConfig, fmt.Errorf("读取配置文件%s失败：%w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &fileConfig); err != nil {
+		return fileConfig, fmt.Errorf("解析配置文件%s失败：%w", path, err)
+	}
+	if err := validateRules(fileConfig.Rules); err != nil {
+		return fileConfig, fmt.Errorf("配置文件%s校验失败：%w", path, err)
+	}
+	if err := validatePolicy(fileConfig.Policy); err != nil {
+		return fileConfig, fmt.Errorf("配置文件%s校验失败：%w", path, err)
+	}
+	if err := validateLanguages(fileConfig.Languages); err != nil {
+		return fileConfig, fmt.Errorf("配置文件%s校验失败：%w", path, err)
+	}
+	if _, err := newRedactor(fileConfig.Redaction); err != nil {
+		return fileConfig, fmt.Errorf("配置文件%s校验失败：%w", path, err)
+	}
+	if err := validateDependencies(fileConfig.Dependencies); err != nil {
+		return fileConfig, fmt.Errorf("配置文件%s校验失败：%w", path, err)
+	}
+	if err := validateTestGap(fileConfig.TestGap); err != nil {
+		return fileConfig, fmt.Errorf("配置文件%s校验失败：%w", path, err)
+	}
+	logDebug("✅【loadFileConfig】已加载配置文件：%s\n", path)
+	return fileConfig, nil
+}
+
+// applyPromptConfig 合并命令行与配置文件中的语言区域、prompt模板目录（命令行优先），并初始化prompt模板集合
+func applyPromptConfig(config *Config) {
+	if config.Locale == "" {
+		config.Locale = config.File.Locale
+	}
+	if config.Locale == "" {
+		config.Locale = LocaleZH
+	}
+	if config.PromptDir == "" {
+		config.PromptDir = config.File.PromptDir
+	}
+	prompts = newPromptSet(config.PromptDir, config.Profile)
+}
+
+// applyRedactionConfig 合并命令行与配置文件中的审计日志路径（命令行优先），并初始化脱敏器
+func applyRedactionConfig(config *Config) error {
+	if config.AuditLog == "" {
+		config.AuditLog = config.File.Redaction.AuditLog
+	}
+	r, err := newRedactor(config.File.Redaction)
+	if err != nil {
+		return err
+	}
+	redactor = r
+	return nil
+}
+
+// applyDependencyConfig 根据配置文件初始化依赖检查器
+func applyDependencyConfig(config *Config) {
+	dependencyChecker = newDependencyChecker(config.File.Dependencies)
+}
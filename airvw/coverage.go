@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CoverageProfile 行覆盖率：文件路径 -> 可执行行号 -> 是否被测试覆盖
+type CoverageProfile struct {
+	files map[string]map[int]bool
+}
+
+// goCoverBlockPattern Go coverprofile中的代码块：文件:起始行.列,结束行.列 语句数 执行次数
+var goCoverBlockPattern = regexp.MustCompile(`^(.+):(\d+)\.\d+,(\d+)\.\d+ \d+ (\d+)$`)
+
+// jacocoGroup JaCoCo XML报告中的分组，多模块报告中package嵌套在group下
+type jacocoGroup struct {
+	Groups   []jacocoGroup `xml:"group"`
+	Packages []struct {
+		Name        string `xml:"name,attr"`
+		SourceFiles []struct {
+			Name  string `xml:"name,attr"`
+			Lines []struct {
+				Nr int `xml:"nr,attr"` // 行号
+				CI int `xml:"ci,attr"` // 已覆盖的指令数
+			} `xml:"line"`
+		} `xml:"sourcefile"`
+	} `xml:"package"`
+}
+
+// loadCoverage 加载覆盖率文件，按内容识别格式：Go coverprofile（mode:开头）、JaCoCo XML、lcov
+func loadCoverage(path string) (*CoverageProfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取覆盖率文件%s失败：%w", path, err)
+	}
+	profile := &CoverageProfile{files: make(map[string]map[int]bool)}
+	text := string(bytes.TrimSpace(data))
+	switch {
+	case strings.HasPrefix(text, "mode:"):
+		profile.parseGo(text)
+	case strings.Contains(text, "<report"):
+		if err := profile.parseJaCoCo(data); err != nil {
+			return nil, fmt.Errorf("解析JaCoCo覆盖率文件%s失败：%w", path, err)
+		}
+	case strings.Contains(text, "SF:"):
+		profile.parseLcov(text)
+	default:
+		return nil, fmt.Errorf("无法识别覆盖率文件%s的格式（支持Go coverprofile、JaCoCo XML、lcov）", path)
+	}
+	logDebug("✅【loadCoverage】已加载覆盖率文件%s：%d个文件\n", path, len(profile.files))
+	return profile, nil
+}
+
+// mark 记录一行的覆盖情况，同一行出现多次时任一次覆盖即视为覆盖
+func (p *CoverageProfile) mark(file string, line int, covered bool) {
+	lines, ok := p.files[file]
+	if !ok {
+		lines = make(map[int]bool)
+		p.files[file] = lines
+	}
+	lines[line] = lines[line] || covered
+}
+
+// parseGo 解析Go coverprofile，代码块覆盖的每一行都视为可执行行
+func (p *CoverageProfile) parseGo(text string) {
+	for _, line := range strings.Split(text, "\n") {
+		matches := goCoverBlockPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		start, _ := strconv.Atoi(matches[2])
+		end, _ := strconv.Atoi(matches[3])
+		covered := matches[4] != "0"
+		for n := start; n <= end; n++ {
+			p.mark(matches[1], n, covered)
+		}
+	}
+}
+
+// parseJaCoCo 解析JaCoCo XML报告，文件路径为包路径/文件名
+func (p *CoverageProfile) parseJaCoCo(data []byte) error {
+	var report jacocoGroup
+	if err := xml.Unmarshal(data, &report); err != nil {
+		return err
+	}
+	var walk func(group jacocoGroup)
+	walk = func(group jacocoGroup) {
+		for _, pkg := range group.Packages {
+			for _, source := range pkg.SourceFiles {
+				file := strings.TrimPrefix(pkg.Name+"/"+source.Name, "/")
+				for _, line := range source.Lines {
+					p.mark(file, line.Nr, line.CI > 0)
+				}
+			}
+		}
+		for _, sub := range group.Groups {
+			walk(sub)
+		}
+	}
+	walk(report)
+	return nil
+}
+
+// parseLcov 解析lcov tracefile
+func (p *CoverageProfile) parseLcov(text string) {
+	file := ""
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(line, "SF:"):
+			file = strings.TrimPrefix(line, "SF:")
+		case strings.HasPrefix(line, "DA:") && file != "":
+			fields := strings.Split(strings.TrimPrefix(line, "DA:"), ",")
+			if len(fields) < 2 {
+				continue
+			}
+			n, err := strconv.Atoi(fields[0])
+			if err != nil {
+				continue
+			}
+			p.mark(file, n, fields[1] != "0")
+		case line == "end_of_record":
+			file = ""
+		}
+	}
+}
+
+// Lookup 查找仓库文件的行覆盖率。覆盖率文件中的路径可能是Go包导入路径、JaCoCo包路径或绝对路径，
+// 与仓库相对路径按目录边界的后缀匹配，多个匹配时取最长的路径
+func (p *CoverageProfile) Lookup(file string) (map[int]bool, bool) {
+	if p == nil {
+		return nil, false
+	}
+	if lines, ok := p.files[file]; ok {
+		return lines, true
+	}
+	best := ""
+	for path := range p.files {
+		if (strings.HasSuffix(path, "/"+file) || strings.HasSuffix(file, "/"+path)) && len(path) > len(best) {
+			best = path
+		}
+	}
+	if best == "" {
+		return nil, false
+	}
+	return p.files[best], true
+}
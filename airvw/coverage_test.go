@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadCoverage 测试Go coverprofile、JaCoCo XML和lcov三种格式的解析，以及覆盖率路径与仓库路径的后缀匹配
+func TestLoadCoverage(t *testing.T) {
+	tests := []struct {
+		name, content string
+		file          string
+		covered       []int
+		uncovered     []int
+	}{
+		{
+			"coverage.out",
+			"mode: set\ngithub.com/acme/order/service/order.go:10.40,12.16 2 1\ngithub.com/acme/order/service/order.go:12.16,14.3 1 0\n",
+			"service/order.go", []int{10, 11, 12}, []int{13, 14},
+		},
+		{
+			"jacoco.xml",
+			`<?xml version="1.0" encoding="UTF-8" standalone="yes"?><!DOCTYPE report PUBLIC "-//JACOCO//DTD Report 1.1//EN" "report.dtd">` +
+				`<report name="order"><group name="order-service"><package name="com/acme/order"><sourcefile name="OrderService.java">` +
+				`<line nr="20" mi="0" ci="3" mb="0" cb="0"/><line nr="21" mi="2" ci="0" mb="0" cb="0"/></sourcefile></package></group></report>`,
+			"order-service/src/main/java/com/acme/order/OrderService.java", []int{20}, []int{21},
+		},
+		{
+			"lcov.info",
+			"TN:\nSF:/builds/acme/web/src/utils/price.ts\nDA:3,5\nDA:4,0\nend_of_record\n",
+			"src/utils/price.ts", []int{3}, []int{4},
+		},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), tt.name)
+		if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		profile, err := loadCoverage(path)
+		if err != nil {
+			t.Fatalf("%s：%v", tt.name, err)
+		}
+		lines, ok := profile.Lookup(tt.file)
+		if !ok {
+			t.Fatalf("%s：未找到%s的覆盖率", tt.name, tt.file)
+		}
+		for _, n := range tt.covered {
+			if covered, executable := lines[n]; !executable || !covered {
+				t.Errorf("%s：第%d行应为已覆盖", tt.name, n)
+			}
+		}
+		for _, n := range tt.uncovered {
+			if covered, executable := lines[n]; !executable || covered {
+				t.Errorf("%s：第%d行应为未覆盖", tt.name, n)
+			}
+		}
+		if _, ok := profile.Lookup("other/" + filepath.Base(tt.file)); ok {
+			t.Errorf("%s：不同目录的同名文件不应匹配", tt.name)
+		}
+	}
+}
@@ -0,0 +1,740 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 依赖变更类型
+const (
+	DependencyAdded      = "added"      // 新增
+	DependencyRemoved    = "removed"    // 删除
+	DependencyUpgraded   = "upgraded"   // 升级
+	DependencyDowngraded = "downgraded" // 降级
+	DependencyChanged    = "changed"    // 版本约束变化但版本号不变，如1.2.3 → ^1.2.3
+)
+
+// dependencyKindNames 依赖变更类型的展示名
+var dependencyKindNames = map[string]string{
+	DependencyAdded: "新增", DependencyRemoved: "删除", DependencyUpgraded: "升级", DependencyDowngraded: "降级", DependencyChanged: "修改约束",
+}
+
+// DependencyConfig 依赖变更评审配置
+type DependencyConfig struct {
+	OSVDatabase      string            `json:"osv_database"`      // 离线漏洞库：OSV导出的zip包、JSON文件或目录
+	LicenseAllowlist []string          `json:"license_allowlist"` // 允许引入的许可证（SPDX标识），为空时不检查许可证
+	Licenses         map[string]string `json:"licenses"`          // 依赖名 -> 许可证，补充无法从锁文件和node_modules中获取的许可证
+}
+
+// DependencyChange 依赖清单或锁文件中的一个依赖变更
+type DependencyChange struct {
+	Ecosystem string // 生态（与OSV一致）：Go/npm/Maven/PyPI
+	Name      string // 依赖名，Maven为groupId:artifactId
+	From      string // 变更前的版本（约束），新增时为空
+	To        string // 变更后的版本（约束），删除时为空
+	Kind      string // 变更类型
+	File      string // 所在文件
+	Line      int    // 新文件行号，删除时为0
+	Lockfile  bool   // 来自锁文件（含间接依赖）
+	License   string // 锁文件中声明的许可证
+}
+
+// DependencyReport 依赖变更及其检查结果
+type DependencyReport struct {
+	DependencyChange
+	Vulnerabilities []Vulnerability // 新版本命中的已知漏洞
+	License         string          // 许可证，未知时为空
+	LicenseDenied   bool            // 许可证不在允许列表中
+	LicenseUnknown  bool            // 配置了许可证允许列表，但无法确定许可证
+}
+
+// dependencyEntry 清单中的一个依赖声明
+type dependencyEntry struct {
+	Name    string
+	Version string
+	Line    int
+	License string
+}
+
+// manifestParser 解析diff一侧（旧文件或新文件）的内容，只返回位于变更行上的依赖声明
+type manifestParser func(lines []diffLine) []dependencyEntry
+
+// dependencyManifest 支持的依赖清单/锁文件
+type dependencyManifest struct {
+	Pattern   string // 文件路径模式（glob）
+	Ecosystem string
+	Lockfile  bool
+	parse     manifestParser
+}
+
+// dependencyManifests 支持的依赖清单和锁文件
+var dependencyManifests = []dependencyManifest{
+	{Pattern: "go.mod", Ecosystem: "Go", parse: parseGoMod},
+	{Pattern: "go.sum", Ecosystem: "Go", Lockfile: true, parse: parseGoSum},
+	{Pattern: "package.json", Ecosystem: "npm", parse: parsePackageJSON},
+	{Pattern: "package-lock.json", Ecosystem: "npm", Lockfile: true, parse: parsePackageLock},
+	{Pattern: "pom.xml", Ecosystem: "Maven", parse: parsePOM},
+	{Pattern: "requirements*.txt", Ecosystem: "PyPI", parse: parseRequirements},
+	{Pattern: "requirements/*.txt", Ecosystem: "PyPI", parse: parseRequirements},
+}
+
+// glob 匹配任意目录下该清单文件的路径模式
+func (m dependencyManifest) glob() string {
+	if strings.Contains(m.Pattern, "/") {
+		return "**/" + m.Pattern
+	}
+	return m.Pattern
+}
+
+// dependencyManifestFiles 依赖清单和锁文件的路径模式
+func dependencyManifestFiles() []string {
+	var files []string
+	for _, m := range dependencyManifests {
+		files = append(files, m.glob())
+	}
+	return files
+}
+
+// lookupManifest 查找文件对应的清单格式
+func lookupManifest(file string) (dependencyManifest, bool) {
+	for _, m := range dependencyManifests {
+		if matchGlob(m.glob(), file) {
+			return m, true
+		}
+	}
+	return dependencyManifest{}, false
+}
+
+// DependencyChecker 依赖变更检查：解析清单和锁文件的diff，查询离线漏洞库和许可证
+type DependencyChecker struct {
+	conf    DependencyConfig
+	allowed map[string]bool
+	once    sync.Once
+	db      *OSVDatabase
+	err     error
+}
+
+// dependencyChecker 全局依赖检查器，main中根据配置文件初始化
+var dependencyChecker = newDependencyChecker(DependencyConfig{})
+
+// newDependencyChecker 根据配置创建依赖检查器，漏洞库在首次检查时加载
+func newDependencyChecker(conf DependencyConfig) *DependencyChecker {
+	c := &DependencyChecker{conf: conf, allowed: make(map[string]bool)}
+	for _, license := range conf.LicenseAllowlist {
+		c.allowed[strings.ToLower(strings.TrimSpace(license))] = true
+	}
+	return c
+}
+
+// validateDependencies 校验依赖评审配置
+func validateDependencies(conf DependencyConfig) error {
+	if conf.OSVDatabase == "" {
+		return nil
+	}
+	if _, err := os.Stat(conf.OSVDatabase); err != nil {
+		return fmt.Errorf("dependencies.osv_database无效：%w", err)
+	}
+	return nil
+}
+
+// database 加载离线漏洞库，未配置时返回nil
+func (c *DependencyChecker) database() (*OSVDatabase, error) {
+	c.once.Do(func() {
+		if c.conf.OSVDatabase != "" {
+			c.db, c.err = loadOSVDatabase(c.conf.OSVDatabase)
+		}
+	})
+	return c.db, c.err
+}
+
+// Check 解析依赖变更并检查已知漏洞和许可证；漏洞库加载失败时仍返回依赖变更，同时返回错误
+func (c *DependencyChecker) Check(repoPath string, diffFiles map[string]string) ([]DependencyReport, error) {
+	changes := parseDependencyChanges(diffFiles)
+	db, err := c.database()
+	reports := make([]DependencyReport, 0, len(changes))
+	for _, change := range changes {
+		report := DependencyReport{DependencyChange: change}
+		if change.Kind != DependencyRemoved {
+			report.Vulnerabilities = db.Lookup(change.Ecosystem, change.Name, concreteVersion(change.To))
+			if len(c.allowed) > 0 {
+				report.License = c.license(repoPath, change)
+				report.LicenseDenied = report.License != "" && !licenseAllowed(report.License, c.allowed)
+				report.LicenseUnknown = report.License == ""
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports, err
+}
+
+// license 依赖的许可证：优先使用配置中声明的许可证，其次为锁文件中的声明，npm依赖最后读取node_modules中的package.json
+func (c *DependencyChecker) license(repoPath string, change DependencyChange) string {
+	if license := c.conf.Licenses[change.Name]; license != "" {
+		return license
+	}
+	if change.License != "" {
+		return change.License
+	}
+	if change.Ecosystem != "npm" {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(repoPath, filepath.Dir(change.File), "node_modules", change.Name, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		License interface{} `json:"license"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+	switch license := pkg.License.(type) {
+	case string:
+		return license
+	case map[string]interface{}:
+		// 旧格式：{"type": "MIT", "url": "..."}
+		if t, ok := license["type"].(string); ok {
+			return t
+		}
+	}
+	return ""
+}
+
+var (
+	// licenseOrPattern SPDX表达式中的OR，兼容「MIT/Apache-2.0」写法
+	licenseOrPattern = regexp.MustCompile(`(?i)\s+or\s+|/`)
+	// licenseAndPattern SPDX表达式中的AND
+	licenseAndPattern = regexp.MustCompile(`(?i)\s+and\s+`)
+)
+
+// licenseAllowed 判断SPDX许可证表达式是否被允许：OR连接的任一选项允许即可，AND连接的许可证需全部允许
+func licenseAllowed(expr string, allowed map[string]bool) bool {
+	expr = strings.NewReplacer("(", "", ")", "").Replace(expr)
+	for _, option := range licenseOrPattern.Split(expr, -1) {
+		ok := true
+		for _, license := range licenseAndPattern.Split(option, -1) {
+			if !allowed[strings.ToLower(strings.TrimSpace(license))] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDependencyChanges 解析各清单/锁文件的依赖变更；锁文件中与清单重复的依赖只保留清单中的变更
+func parseDependencyChanges(diffFiles map[string]string) []DependencyChange {
+	var files []string
+	for file := range diffFiles {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var manifestChanges, lockChanges []DependencyChange
+	declared := make(map[string]bool)
+	for _, file := range files {
+		manifest, ok := lookupManifest(file)
+		if !ok {
+			continue
+		}
+		changes := diffDependencies(manifest, file, diffFiles[file])
+		logDebug("📦【parseDependencyChanges】%s：%d个依赖变更\n", file, len(changes))
+		if manifest.Lockfile {
+			lockChanges = append(lockChanges, changes...)
+			continue
+		}
+		for _, change := range changes {
+			declared[dependencyKey(change.Ecosystem, change.Name)] = true
+		}
+		manifestChanges = append(manifestChanges, changes...)
+	}
+	for _, change := range lockChanges {
+		if !declared[dependencyKey(change.Ecosystem, change.Name)] {
+			manifestChanges = append(manifestChanges, change)
+		}
+	}
+	return manifestChanges
+}
+
+// diffDependencies 对比文件diff两侧变更行上的依赖声明，得到新增/删除/升级/降级的依赖
+func diffDependencies(manifest dependencyManifest, file, diff string) []DependencyChange {
+	var oldLines, newLines []diffLine
+	for _, line := range parseDiffLines(diff) {
+		switch line.Kind {
+		case '-':
+			oldLines = append(oldLines, line)
+		case '+':
+			newLines = append(newLines, line)
+		default:
+			oldLines = append(oldLines, line)
+			newLines = append(newLines, line)
+		}
+	}
+	before := latestEntries(manifest.Ecosystem, manifest.parse(oldLines))
+	after := latestEntries(manifest.Ecosystem, manifest.parse(newLines))
+
+	var keys []string
+	for key := range before {
+		keys = append(keys, key)
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []DependencyChange
+	for _, key := range keys {
+		old, hadOld := before[key]
+		cur, hasNew := after[key]
+		change := DependencyChange{Ecosystem: manifest.Ecosystem, File: file, Lockfile: manifest.Lockfile}
+		switch {
+		case !hasNew:
+			change.Name, change.From, change.Kind = old.Name, old.Version, DependencyRemoved
+		case !hadOld:
+			change.Name, change.To, change.Kind, change.Line, change.License = cur.Name, cur.Version, DependencyAdded, cur.Line, cur.License
+		default:
+			if old.Version == cur.Version {
+				continue
+			}
+			change.Name, change.From, change.To, change.Line, change.License = cur.Name, old.Version, cur.Version, cur.Line, cur.License
+			switch c := compareVersions(concreteVersion(cur.Version), concreteVersion(old.Version)); {
+			case c > 0:
+				change.Kind = DependencyUpgraded
+			case c < 0:
+				change.Kind = DependencyDowngraded
+			default:
+				change.Kind = DependencyChanged
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// latestEntries 按依赖归并声明，同一依赖出现多个版本时（如go.sum、嵌套的node_modules）取最高版本
+func latestEntries(ecosystem string, entries []dependencyEntry) map[string]dependencyEntry {
+	latest := make(map[string]dependencyEntry)
+	for _, entry := range entries {
+		key := dependencyKey(ecosystem, entry.Name)
+		if existing, ok := latest[key]; ok && compareVersions(concreteVersion(entry.Version), concreteVersion(existing.Version)) <= 0 {
+			continue
+		}
+		latest[key] = entry
+	}
+	return latest
+}
+
+// goMajorSuffixPattern Go模块路径中的主版本后缀，如/v2
+var goMajorSuffixPattern = regexp.MustCompile(`/v\d+$`)
+
+// dependencyKey 依赖的归并键：PyPI包名按PEP 503归一化，Go模块忽略主版本后缀，使v1 → v2识别为升级
+func dependencyKey(ecosystem, name string) string {
+	if ecosystem == "Go" {
+		name = goMajorSuffixPattern.ReplaceAllString(name, "")
+	}
+	return osvKey(ecosystem, name)
+}
+
+// concreteVersionPattern 版本约束中的版本号
+var concreteVersionPattern = regexp.MustCompile(`\d[\w.+-]*`)
+
+// concreteVersion 从版本约束中提取版本号（如^4.17.21 → 4.17.21、>=1.0,<2 → 1.0），用于比较和漏洞查询
+func concreteVersion(spec string) string {
+	return concreteVersionPattern.FindString(spec)
+}
+
+// majorVersion 版本号的主版本，0.x版本的次版本号变化同样视为不兼容
+func majorVersion(version string) string {
+	tokens := versionTokens(concreteVersion(version))
+	if len(tokens) == 0 {
+		return ""
+	}
+	if tokens[0] == "0" && len(tokens) > 1 {
+		return "0." + tokens[1]
+	}
+	return tokens[0]
+}
+
+// MajorUpgrade 是否为主版本升级
+func (c DependencyChange) MajorUpgrade() bool {
+	return c.Kind == DependencyUpgraded && majorVersion(c.From) != majorVersion(c.To)
+}
+
+// goBlockPattern go.mod中的块起始行，如require (
+var goBlockPattern = regexp.MustCompile(`^(require|replace|exclude|retract)\s*\(`)
+
+// goRequirePattern go.mod中的依赖声明
+var goRequirePattern = regexp.MustCompile(`^(?:require\s+)?([A-Za-z0-9][\w.~/-]*)\s+(v\d\S*)`)
+
+// parseGoMod 解析go.mod中的require声明，hunk从块中间开始时按require处理
+func parseGoMod(lines []diffLine) []dependencyEntry {
+	var entries []dependencyEntry
+	block := ""
+	for _, line := range lines {
+		if line.Kind == '@' {
+			block = "require"
+			continue
+		}
+		text := strings.TrimSpace(line.Text)
+		if matches := goBlockPattern.FindStringSubmatch(text); matches != nil {
+			block = matches[1]
+			continue
+		}
+		if text == ")" {
+			block = ""
+			continue
+		}
+		if line.Kind == ' ' || (block == "" && !strings.HasPrefix(text, "require ")) || (block != "" && block != "require") {
+			continue
+		}
+		if matches := goRequirePattern.FindStringSubmatch(text); matches != nil {
+			entries = append(entries, dependencyEntry{Name: matches[1], Version: matches[2], Line: line.NewNo})
+		}
+	}
+	return entries
+}
+
+// goSumPattern go.sum中的校验和记录
+var goSumPattern = regexp.MustCompile(`^(\S+) (v[^\s/]+)(?:/go\.mod)? h1:`)
+
+// parseGoSum 解析go.sum中的模块版本
+func parseGoSum(lines []diffLine) []dependencyEntry {
+	var entries []dependencyEntry
+	for _, line := range lines {
+		if line.Kind == '@' || line.Kind == ' ' {
+			continue
+		}
+		if matches := goSumPattern.FindStringSubmatch(strings.TrimSpace(line.Text)); matches != nil {
+			entries = append(entries, dependencyEntry{Name: matches[1], Version: matches[2], Line: line.NewNo})
+		}
+	}
+	return entries
+}
+
+var (
+	// jsonObjectKeyPattern JSON中对象类型字段的起始行，如"dependencies": {
+	jsonObjectKeyPattern = regexp.MustCompile(`^\s*"([^"]*)"\s*:\s*\{`)
+	// jsonStringFieldPattern JSON中的字符串字段，如"lodash": "^4.17.21"
+	jsonStringFieldPattern = regexp.MustCompile(`^\s*"([^"]+)"\s*:\s*"([^"]*)"`)
+	// npmVersionSpecPattern npm版本约束
+	npmVersionSpecPattern = regexp.MustCompile(`^(?:[\^~]|[<>]=?|=)?\s*v?\d`)
+)
+
+// npmDependencySections package.json中声明依赖的字段
+var npmDependencySections = map[string]bool{"dependencies": true, "devDependencies": true, "peerDependencies": true, "optionalDependencies": true}
+
+// npmMetadataKeys package.json中形似版本约束但不是依赖的字段
+var npmMetadataKeys = map[string]bool{"version": true, "node": true, "npm": true, "yarn": true, "pnpm": true}
+
+// parsePackageJSON 解析package.json中各依赖字段的声明；hunk中看不到所在字段时，按值是否为版本约束判断
+func parsePackageJSON(lines []diffLine) []dependencyEntry {
+	var entries []dependencyEntry
+	section := "?"
+	for _, line := range lines {
+		if line.Kind == '@' {
+			section = "?"
+			continue
+		}
+		if matches := jsonObjectKeyPattern.FindStringSubmatch(line.Text); matches != nil {
+			section = matches[1]
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line.Text), "}") {
+			section = ""
+			continue
+		}
+		if line.Kind == ' ' {
+			continue
+		}
+		matches := jsonStringFieldPattern.FindStringSubmatch(line.Text)
+		if matches == nil {
+			continue
+		}
+		if npmDependencySections[section] || (section == "?" && !npmMetadataKeys[matches[1]] && npmVersionSpecPattern.MatchString(matches[2])) {
+			entries = append(entries, dependencyEntry{Name: matches[1], Version: matches[2], Line: line.NewNo})
+		}
+	}
+	return entries
+}
+
+// npmLockPackagePattern package-lock.json中的包记录起始行，v2/v3为"node_modules/名称": {，v1为"名称": {
+var npmLockPackagePattern = regexp.MustCompile(`^\s*"(?:[^"]*node_modules/)?((?:@[^/"]+/)?[^/"]+)"\s*:\s*\{`)
+
+// npmLockFields package-lock.json中对象类型的非包字段
+var npmLockFields = map[string]bool{
+	"packages": true, "dependencies": true, "requires": true, "engines": true, "bin": true, "funding": true,
+	"devDependencies": true, "peerDependencies": true, "optionalDependencies": true, "peerDependenciesMeta": true,
+}
+
+// parsePackageLock 解析package-lock.json中各包的version和license字段
+func parsePackageLock(lines []diffLine) []dependencyEntry {
+	var entries []dependencyEntry
+	current := ""
+	index := make(map[string]int)
+	for _, line := range lines {
+		if line.Kind == '@' {
+			current = ""
+			continue
+		}
+		if matches := npmLockPackagePattern.FindStringSubmatch(line.Text); matches != nil {
+			if !npmLockFields[matches[1]] || strings.Contains(line.Text, "node_modules/") {
+				current = matches[1]
+			}
+			continue
+		}
+		matches := jsonStringFieldPattern.FindStringSubmatch(line.Text)
+		if matches == nil || current == "" || line.Kind == ' ' {
+			continue
+		}
+		switch matches[1] {
+		case "version":
+			index[current] = len(entries)
+			entries = append(entries, dependencyEntry{Name: current, Version: matches[2], Line: line.NewNo})
+		case "license":
+			if i, ok := index[current]; ok {
+				entries[i].License = matches[2]
+			}
+		}
+	}
+	return entries
+}
+
+var (
+	// pomBlockPattern pom.xml中的依赖块：<dependency>/<parent>
+	pomBlockPattern = regexp.MustCompile(`<(/?)(dependency|parent|exclusions)>`)
+	// pomFieldPattern 依赖块中的坐标字段
+	pomFieldPattern = regexp.MustCompile(`<(groupId|artifactId|version)>\s*([^<\s]+)\s*</`)
+)
+
+// parsePOM 解析pom.xml中<dependency>和<parent>块的坐标，块中任一行变更即视为该依赖变更。
+// hunk从块中间开始时，只有看到块结束标记才确认处于依赖块中，避免把项目自身的版本号识别为依赖
+func parsePOM(lines []diffLine) []dependencyEntry {
+	var entries []dependencyEntry
+	type block struct {
+		group, artifact, version string
+		line                     int
+		changed                  bool
+	}
+	var cur block
+	state := "" // ""：块外，"in"：块内，"?"：未知
+	inExclusions := false
+	flush := func() {
+		if cur.artifact != "" && cur.changed {
+			name := cur.artifact
+			if cur.group != "" {
+				name = cur.group + ":" + cur.artifact
+			}
+			entries = append(entries, dependencyEntry{Name: name, Version: cur.version, Line: cur.line})
+		}
+		cur = block{}
+	}
+	for _, line := range lines {
+		if line.Kind == '@' {
+			if state == "in" {
+				flush()
+			}
+			cur, state, inExclusions = block{}, "?", false
+			continue
+		}
+		for _, matches := range pomBlockPattern.FindAllStringSubmatch(line.Text, -1) {
+			closing := matches[1] == "/"
+			switch {
+			case matches[2] == "exclusions":
+				inExclusions = !closing
+			case closing:
+				if state != "" {
+					flush()
+				}
+				state = ""
+			default:
+				cur, state = block{}, "in"
+			}
+		}
+		if state == "" || inExclusions {
+			continue
+		}
+		changed := line.Kind != ' '
+		for _, matches := range pomFieldPattern.FindAllStringSubmatch(line.Text, -1) {
+			switch matches[1] {
+			case "groupId":
+				cur.group = matches[2]
+			case "artifactId":
+				cur.artifact = matches[2]
+			case "version":
+				cur.version = matches[2]
+				if changed {
+					// 优先指向变更的版本号所在行
+					cur.line = line.NewNo
+				}
+			}
+		}
+		if changed {
+			cur.changed = true
+			if cur.line == 0 {
+				cur.line = line.NewNo
+			}
+		}
+	}
+	if state == "in" {
+		flush()
+	}
+	return entries
+}
+
+// requirementPattern requirements.txt中的依赖声明，如requests[socks]==2.31.0
+var requirementPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*((?:===|==|~=|!=|>=|<=|>|<)[^;#]*)?`)
+
+// parseRequirements 解析requirements.txt中的依赖声明，跳过注释、选项（-r/-e等）和URL
+func parseRequirements(lines []diffLine) []dependencyEntry {
+	var entries []dependencyEntry
+	for _, line := range lines {
+		text := strings.TrimSpace(line.Text)
+		if line.Kind == '@' || line.Kind == ' ' || text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "-") || strings.Contains(text, "://") {
+			continue
+		}
+		if matches := requirementPattern.FindStringSubmatch(text); matches != nil {
+			version := strings.ReplaceAll(strings.TrimSpace(matches[2]), " ", "")
+			entries = append(entries, dependencyEntry{Name: matches[1], Version: version, Line: line.NewNo})
+		}
+	}
+	return entries
+}
+
+// runDependencyLint 依赖清单的检查流程：列出依赖变更及漏洞、许可证检查结果，供模型评估升级风险
+func runDependencyLint(repoPath string, diffFiles map[string]string) map[string][]string {
+	reports, err := dependencyChecker.Check(repoPath, diffFiles)
+	sections := make(map[string][]string)
+	if err != nil {
+		for file := range diffFiles {
+			sections[file] = append(sections[file], fmt.Sprintf("漏洞检查未执行：%s", err))
+		}
+	}
+	lines := make(map[string][]string)
+	for _, report := range reports {
+		lines[report.File] = append(lines[report.File], "- "+report.describe())
+	}
+	for file, items := range lines {
+		sections[file] = append(sections[file], fmt.Sprintf("依赖变更（%d项）：\n%s", len(items), strings.Join(items, "\n")))
+	}
+	return sections
+}
+
+// describe 依赖变更及检查结果的描述
+func (r DependencyReport) describe() string {
+	var b strings.Builder
+	b.WriteString("[" + dependencyKindNames[r.Kind] + "] " + r.Name + " ")
+	switch r.Kind {
+	case DependencyAdded:
+		b.WriteString(firstNonEmpty(r.To, "未指定版本"))
+	case DependencyRemoved:
+		b.WriteString(firstNonEmpty(r.From, "未指定版本"))
+	default:
+		b.WriteString(r.From + " → " + r.To)
+	}
+	var notes []string
+	if r.Line > 0 {
+		notes = append(notes, fmt.Sprintf("第%d行", r.Line))
+	}
+	if r.Lockfile {
+		notes = append(notes, "锁文件")
+	}
+	if r.MajorUpgrade() {
+		notes = append(notes, "主版本升级")
+	}
+	for _, v := range r.Vulnerabilities {
+		note := "已知漏洞" + vulnerabilityIDs(v)
+		if v.Severity != "" {
+			note += "，" + v.Severity
+		}
+		if v.Fixed != "" {
+			note += "，修复版本" + v.Fixed
+		}
+		notes = append(notes, note)
+	}
+	if r.LicenseDenied {
+		notes = append(notes, "许可证"+r.License+"不在允许列表中")
+	} else if r.LicenseUnknown {
+		notes = append(notes, "许可证未知，需人工确认")
+	}
+	if len(notes) > 0 {
+		b.WriteString("（" + strings.Join(notes, "；") + "）")
+	}
+	return b.String()
+}
+
+// vulnerabilityLevels OSV严重程度对应的问题等级，未知时为high
+var vulnerabilityLevels = map[string]string{"CRITICAL": LevelBlock, "HIGH": LevelHigh, "MODERATE": LevelMedium, "MEDIUM": LevelMedium, "LOW": LevelSuggest}
+
+// scanDependencies 依赖变更的确定性检查：新版本命中已知漏洞、许可证不在允许列表中时生成问题
+func scanDependencies(repoPath string, diffFiles map[string]string, locale string) ([]BlockIssue, error) {
+	reports, err := dependencyChecker.Check(repoPath, diffFiles)
+	if err != nil {
+		return nil, err
+	}
+	var issues []BlockIssue
+	for _, r := range reports {
+		if r.Line == 0 {
+			continue
+		}
+		for _, v := range r.Vulnerabilities {
+			level, ok := vulnerabilityLevels[v.Severity]
+			if !ok {
+				level = LevelHigh
+			}
+			suggestion := tr(locale, "dependency_vuln_unfixed")
+			if v.Fixed != "" {
+				suggestion = tr(locale, "dependency_vuln_fixed", v.Fixed)
+			}
+			logDebug("📦【scanDependencies】%s@%s命中漏洞%s\n", r.Name, r.To, v.ID)
+			issues = append(issues, BlockIssue{
+				Level:      level,
+				File:       r.File,
+				Line:       strconv.Itoa(r.Line),
+				Issue:      tr(locale, "dependency_vuln_issue", r.Name, r.To, vulnerabilityIDs(v), firstNonEmpty(v.Summary, v.ID)),
+				Suggestion: suggestion,
+				RuleID:     "DEP-VULN",
+				Scanner:    ScannerDependency,
+				CWE:        v.CWE,
+				OWASP:      "A06:2021",
+			})
+		}
+		if r.LicenseDenied {
+			issues = append(issues, BlockIssue{
+				Level:      LevelHigh,
+				File:       r.File,
+				Line:       strconv.Itoa(r.Line),
+				Issue:      tr(locale, "dependency_license_issue", r.Name, r.To, r.License),
+				Suggestion: tr(locale, "dependency_license_suggestion"),
+				RuleID:     "DEP-LICENSE",
+				Scanner:    ScannerDependency,
+			})
+		}
+	}
+	if len(issues) > 0 {
+		logDebug("📦【scanDependencies】共发现%d个依赖问题\n", len(issues))
+	}
+	return issues, nil
+}
+
+// vulnerabilityIDs 漏洞编号及其CVE别名，如GHSA-xxxx/CVE-2021-23337
+func vulnerabilityIDs(v Vulnerability) string {
+	ids := []string{v.ID}
+	for _, alias := range v.Aliases {
+		if strings.HasPrefix(alias, "CVE-") {
+			ids = append(ids, alias)
+		}
+	}
+	return strings.Join(ids, "/")
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestDiffDependencies 测试各类清单和锁文件的依赖变更解析
+func TestDiffDependencies(t *testing.T) {
+	tests := []struct {
+		file string
+		diff string
+		want []string // 类型 名称 变更前 变更后 行号
+	}{
+		{
+			"go.mod",
+			"@@ -3,6 +3,7 @@\n go 1.21\n \n require (\n-\tgithub.com/gin-gonic/gin v1.9.0\n+\tgithub.com/gin-gonic/gin v1.9.1\n-\tgithub.com/jackc/pgx/v4 v4.18.1\n+\tgithub.com/jackc/pgx/v5 v5.4.3\n+\tgolang.org/x/net v0.7.0 // indirect\n )\n",
+			[]string{"upgraded github.com/gin-gonic/gin v1.9.0 v1.9.1 6", "upgraded github.com/jackc/pgx/v5 v4.18.1 v5.4.3 7", "added golang.org/x/net  v0.7.0 8"},
+		},
+		{
+			"web/package.json",
+			"@@ -1,9 +1,9 @@\n {\n-  \"version\": \"1.0.0\",\n+  \"version\": \"1.1.0\",\n   \"dependencies\": {\n-    \"lodash\": \"^3.10.1\",\n+    \"lodash\": \"^4.17.20\",\n-    \"moment\": \"^2.29.1\"\n+    \"dayjs\": \"^1.11.9\"\n   },\n   \"scripts\": {\n-    \"build\": \"vite build\"\n+    \"build\": \"vite build --mode prod\"\n",
+			[]string{"added dayjs  ^1.11.9 5", "upgraded lodash ^3.10.1 ^4.17.20 4", "removed moment ^2.29.1  0"},
+		},
+		{
+			"package-lock.json",
+			"@@ -20,9 +20,9 @@\n     \"node_modules/lodash\": {\n-      \"version\": \"4.17.20\",\n-      \"resolved\": \"https://registry.npmjs.org/lodash/-/lodash-4.17.20.tgz\",\n+      \"version\": \"4.17.21\",\n+      \"resolved\": \"https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz\",\n       \"license\": \"MIT\"\n     },\n",
+			[]string{"upgraded lodash 4.17.20 4.17.21 21"},
+		},
+		{
+			"pom.xml",
+			"@@ -30,12 +30,12 @@\n     <dependency>\n       <groupId>com.alibaba</groupId>\n       <artifactId>fastjson</artifactId>\n-      <version>1.2.68</version>\n+      <version>2.0.40</version>\n       <exclusions>\n         <exclusion>\n           <groupId>org.slf4j</groupId>\n           <artifactId>slf4j-api</artifactId>\n         </exclusion>\n       </exclusions>\n     </dependency>\n@@ -60,3 +60,3 @@\n   <artifactId>order-service</artifactId>\n-  <version>1.0.0</version>\n+  <version>1.0.1</version>\n   <packaging>jar</packaging>\n",
+			[]string{"upgraded com.alibaba:fastjson 1.2.68 2.0.40 33"},
+		},
+		{
+			"requirements.txt",
+			"@@ -1,3 +1,4 @@\n-requests==2.25.0\n+requests[socks]==2.31.0\n -r base.txt\n+Django>=4.2,<5.0  # LTS\n+-e git+https://github.com/x/y.git#egg=y\n",
+			[]string{"added Django  >=4.2,<5.0 3", "upgraded requests ==2.25.0 ==2.31.0 1"},
+		},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, c := range parseDependencyChanges(map[string]string{tt.file: tt.diff}) {
+			got = append(got, strings.Join([]string{c.Kind, c.Name, c.From, c.To, strconv.Itoa(c.Line)}, " "))
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s的依赖变更 =\n%s\n期望\n%s", tt.file, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+// TestScanDependencies 测试新版本命中漏洞库和许可证不在允许列表中时生成问题，锁文件中与清单重复的依赖只报告一次
+func TestScanDependencies(t *testing.T) {
+	dir := t.TempDir()
+	osv := `[{
+		"id": "GHSA-35jh-r3h4-6jhm", "aliases": ["CVE-2021-23337"], "summary": "Command Injection in lodash",
+		"affected": [{"package": {"ecosystem": "npm", "name": "lodash"}, "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]}],
+		"database_specific": {"severity": "HIGH", "cwe_ids": ["CWE-77", "CWE-94"]}
+	}]`
+	if err := os.WriteFile(filepath.Join(dir, "npm.json"), []byte(osv), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(old *DependencyChecker) { dependencyChecker = old }(dependencyChecker)
+	dependencyChecker = newDependencyChecker(DependencyConfig{
+		OSVDatabase:      dir,
+		LicenseAllowlist: []string{"MIT", "Apache-2.0"},
+		Licenses:         map[string]string{"left-pad": "WTFPL"},
+	})
+
+	diffFiles := map[string]string{
+		"package.json":      "@@ -2,3 +2,4 @@\n   \"dependencies\": {\n-    \"lodash\": \"^3.10.1\",\n+    \"lodash\": \"^4.17.20\",\n+    \"left-pad\": \"1.3.0\",\n     \"react\": \"^18.2.0\"\n",
+		"package-lock.json": "@@ -20,3 +20,3 @@\n     \"node_modules/lodash\": {\n-      \"version\": \"3.10.1\",\n+      \"version\": \"4.17.20\",\n",
+	}
+	issues, err := scanDependencies(dir, diffFiles, LocaleZH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("scanDependencies() = %+v，期望2个问题", issues)
+	}
+	found := make(map[string]BlockIssue)
+	for _, issue := range issues {
+		found[issue.RuleID] = issue
+	}
+	vuln, license := found["DEP-VULN"], found["DEP-LICENSE"]
+	if vuln.RuleID != "DEP-VULN" || vuln.Level != LevelHigh || vuln.File != "package.json" || vuln.Line != "3" || vuln.CWE != "CWE-77" ||
+		!strings.Contains(vuln.Issue, "GHSA-35jh-r3h4-6jhm/CVE-2021-23337") || !strings.Contains(vuln.Suggestion, "4.17.21") {
+		t.Errorf("漏洞问题 = %+v", vuln)
+	}
+	if license.RuleID != "DEP-LICENSE" || license.Line != "4" || !strings.Contains(license.Issue, "WTFPL") {
+		t.Errorf("许可证问题 = %+v", license)
+	}
+
+	sections := runDependencyLint(dir, diffFiles)
+	if lint := strings.Join(sections["package.json"], "\n"); !strings.Contains(lint, "主版本升级") || !strings.Contains(lint, "已知漏洞GHSA-35jh-r3h4-6jhm") {
+		t.Errorf("检查结果缺少主版本升级或漏洞说明：\n%s", lint)
+	}
+}
+
+// TestLicenseAllowed 测试SPDX表达式的允许判断
+func TestLicenseAllowed(t *testing.T) {
+	allowed := map[string]bool{"mit": true, "apache-2.0": true}
+	for expr, want := range map[string]bool{"MIT": true, "(MIT OR GPL-3.0)": true, "MIT AND GPL-3.0": false, "GPL-3.0": false, "MIT/Apache-2.0": true} {
+		if got := licenseAllowed(expr, allowed); got != want {
+			t.Errorf("licenseAllowed(%q) = %v，期望%v", expr, got, want)
+		}
+	}
+}
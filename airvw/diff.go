@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// parseGitDiff 将git diff输出（可包含多个文件）解析为DiffItem列表，用于本地渲染prompt等离线场景
+func parseGitDiff(text string) []DiffItem {
+	var items []DiffItem
+	var current *DiffItem
+	var hunk strings.Builder
+	inHunk := false
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		current.Diff = hunk.String()
+		items = append(items, *current)
+		current = nil
+		hunk.Reset()
+		inHunk = false
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(trimmed, "diff --git "):
+			flush()
+			current = &DiffItem{}
+			// diff --git a/old b/new
+			fields := strings.SplitN(strings.TrimPrefix(trimmed, "diff --git "), " b/", 2)
+			if len(fields) == 2 {
+				current.OldPath = strings.TrimPrefix(fields[0], "a/")
+				current.NewPath = fields[1]
+			}
+			continue
+		case current == nil && strings.HasPrefix(trimmed, "--- "):
+			// 不带diff --git头的单文件unified diff
+			current = &DiffItem{}
+		case current == nil:
+			continue
+		}
+
+		if inHunk {
+			hunk.WriteString(line)
+			continue
+		}
+		switch {
+		case strings.HasPrefix(trimmed, "new file mode"):
+			current.NewFile = true
+		case strings.HasPrefix(trimmed, "deleted file mode"):
+			current.DeletedFile = true
+		case strings.HasPrefix(trimmed, "rename from "):
+			current.RenamedFile = true
+			current.OldPath = strings.TrimPrefix(trimmed, "rename from ")
+		case strings.HasPrefix(trimmed, "rename to "):
+			current.RenamedFile = true
+			current.NewPath = strings.TrimPrefix(trimmed, "rename to ")
+		case strings.HasPrefix(trimmed, "Binary files "):
+			current.Binary = true
+		case strings.HasPrefix(trimmed, "--- "):
+			if oldPath := diffHeaderPath(trimmed[4:], "a/"); oldPath != "" {
+				current.OldPath = oldPath
+			} else {
+				current.NewFile = true
+			}
+		case strings.HasPrefix(trimmed, "+++ "):
+			if newPath := diffHeaderPath(trimmed[4:], "b/"); newPath != "" {
+				current.NewPath = newPath
+			} else {
+				current.DeletedFile = true
+				current.NewPath = ""
+			}
+		case strings.HasPrefix(trimmed, "@@"):
+			inHunk = true
+			hunk.WriteString(line)
+		}
+	}
+	flush()
+	return items
+}
+
+// diffHeaderPath 解析---/+++行中的文件路径，/dev/null返回空串
+func diffHeaderPath(header, prefix string) string {
+	// 去掉可能存在的时间戳（制表符分隔）
+	if idx := strings.IndexByte(header, '\t'); idx >= 0 {
+		header = header[:idx]
+	}
+	if header == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(header, prefix)
+}
+
+// hunkHeaderPattern 匹配hunk头：@@ -旧起始行,旧行数 +新起始行,新行数 @@
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// diffLine unified diff中的一行
+type diffLine struct {
+	Kind  byte   // '+'新增行，'-'删除行，' '上下文行，'@'hunk头，0为hunk之外的行
+	OldNo int    // 旧文件行号，新增行为0
+	NewNo int    // 新文件行号，删除行为0
+	Text  string // 去掉前缀符号后的内容，hunk头和hunk之外的行为原始内容
+}
+
+// parseDiffLines 按hunk头计算unified diff中每一行在新旧文件中的行号
+func parseDiffLines(diff string) []diffLine {
+	var lines []diffLine
+	oldNo, newNo := 0, 0
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if matches := hunkHeaderPattern.FindStringSubmatch(line); matches != nil {
+			oldNo, _ = strconv.Atoi(matches[1])
+			newNo, _ = strconv.Atoi(matches[2])
+			lines = append(lines, diffLine{Kind: '@', Text: line})
+			continue
+		}
+		if newNo == 0 && oldNo == 0 {
+			lines = append(lines, diffLine{Text: line})
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "+"):
+			lines = append(lines, diffLine{Kind: '+', NewNo: newNo, Text: line[1:]})
+			newNo++
+		case strings.HasPrefix(line, "-"):
+			lines = append(lines, diffLine{Kind: '-', OldNo: oldNo, Text: line[1:]})
+			oldNo++
+		case strings.HasPrefix(line, "\\"):
+			// \ No newline at end of file
+		default:
+			// 上下文行，部分工具会去掉空行前的空格
+			lines = append(lines, diffLine{Kind: ' ', OldNo: oldNo, NewNo: newNo, Text: strings.TrimPrefix(line, " ")})
+			oldNo++
+			newNo++
+		}
+	}
+	return lines
+}
+
+// diffNewLines 提取diff中新文件一侧可见的行（新增行和上下文行）：新文件行号 -> 行内容
+func diffNewLines(diff string) map[int]string {
+	lines := make(map[int]string)
+	for _, line := range parseDiffLines(diff) {
+		if line.Kind == '+' || line.Kind == ' ' {
+			lines[line.NewNo] = line.Text
+		}
+	}
+	return lines
+}
+
+// diffLineKinds 标记diff中新文件一侧各行的类型：新文件行号 -> '+'（新增行）或' '（上下文行）
+func diffLineKinds(diff string) map[int]byte {
+	kinds := make(map[int]byte)
+	for _, line := range parseDiffLines(diff) {
+		if line.Kind == '+' || line.Kind == ' ' {
+			kinds[line.NewNo] = line.Kind
+		}
+	}
+	return kinds
+}
+
+// annotateDiff 为diff的每一行标注新文件行号，格式为「行号 +|  代码」，删除行没有新文件行号，以「     - 代码」标识，
+// 模型直接引用行首的行号即可，无需根据hunk头推算
+func annotateDiff(diff string) string {
+	lines := parseDiffLines(diff)
+	width := 1
+	for _, line := range lines {
+		if w := len(strconv.Itoa(line.NewNo)); w > width {
+			width = w
+		}
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		switch line.Kind {
+		case '+', ' ':
+			fmt.Fprintf(&b, "%*d %c %s\n", width, line.NewNo, line.Kind, line.Text)
+		case '-':
+			fmt.Fprintf(&b, "%*s - %s\n", width, "", line.Text)
+		default:
+			b.WriteString(line.Text + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+// TestAnnotateDiff 测试新文件行号标注：新增行和上下文行带行号，删除行不带行号，多个hunk分别计数
+func TestAnnotateDiff(t *testing.T) {
+	diff := "@@ -8,4 +8,5 @@ func a() {\n" +
+		" \tx := 1\n" +
+		"-\ty := 1\n" +
+		"+\ty := 2\n" +
+		"+\tz := 3\n" +
+		" \treturn\n" +
+		"\\ No newline at end of file\n" +
+		"@@ -98,2 +99,2 @@\n" +
+		"-\tfoo()\n" +
+		"+\tbar()\n" +
+		" }\n"
+
+	want := "@@ -8,4 +8,5 @@ func a() {\n" +
+		"  8   \tx := 1\n" +
+		"    - \ty := 1\n" +
+		"  9 + \ty := 2\n" +
+		" 10 + \tz := 3\n" +
+		" 11   \treturn\n" +
+		"@@ -98,2 +99,2 @@\n" +
+		"    - \tfoo()\n" +
+		" 99 + \tbar()\n" +
+		"100   }\n"
+	if got := annotateDiff(diff); got != want {
+		t.Errorf("annotateDiff() =\n%s\n期望\n%s", got, want)
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// levelSynonyms 模型可能输出的等级写法（小写）-> 规范等级
+var levelSynonyms = map[string]string{
+	"block": LevelBlock, "blocker": LevelBlock, "blocking": LevelBlock, "critical": LevelBlock, "fatal": LevelBlock,
+	"阻断": LevelBlock, "阻断级": LevelBlock, "致命": LevelBlock,
+	"high": LevelHigh, "major": LevelHigh, "severe": LevelHigh,
+	"高": LevelHigh, "高级": LevelHigh, "高级别": LevelHigh, "高风险": LevelHigh, "严重": LevelHigh,
+	"medium": LevelMedium, "moderate": LevelMedium, "warning": LevelMedium,
+	"中": LevelMedium, "中级": LevelMedium, "中级别": LevelMedium, "中风险": LevelMedium,
+	"suggest": LevelSuggest, "suggestion": LevelSuggest, "low": LevelSuggest, "minor": LevelSuggest, "info": LevelSuggest,
+	"建议": LevelSuggest, "优化建议": LevelSuggest, "低": LevelSuggest, "低风险": LevelSuggest,
+}
+
+// levelEmojis 仅以emoji标识等级时的映射
+var levelEmojis = map[string]string{
+	"🔴": LevelBlock, "⛔": LevelBlock, "🚫": LevelBlock, "❌": LevelBlock,
+	"🟠": LevelHigh, "⚠️": LevelHigh, "⚠": LevelHigh,
+	"🟡": LevelMedium,
+	"🔵": LevelSuggest, "🟢": LevelSuggest, "💡": LevelSuggest,
+}
+
+// findingTagPattern 匹配行首的等级标签及可选的规则ID标签，兼容全角括号
+var findingTagPattern = regexp.MustCompile(`^[\[【]([^\]】]+)[\]】](?:\s*[\[【]([^\]】]+)[\]】])?\s*`)
+
+// findingLocationPattern 匹配「文件:行号 - 描述」
+var findingLocationPattern = regexp.MustCompile(`^([^:：\s][^:：]*?)\s*[:：]\s*(\d+)\s*[-—–]\s*(.+)$`)
+
+// confidencePattern 匹配行尾的置信度，如「(置信度:0.85)」「- confidence: 0.6」「（置信度 80%）」
+var confidencePattern = regexp.MustCompile(`(?i)\s*(?:[-|]\s*)?[(（\[]?\s*(?:置信度|confidence)\s*[:：=]?\s*(\d+(?:\.\d+)?)\s*(%)?\s*[)）\]]?\s*$`)
+
+// securityTagPattern 匹配CWE编号和OWASP Top 10分类标签，如[CWE-89]、【A03:2021】、[A03:2021-Injection]，兼容全角括号
+var securityTagPattern = regexp.MustCompile(`(?i)[\[【]\s*(?:(CWE-\d+)|(A(?:0[1-9]|10))(?::(20\d\d))?(?:\s*[-–—]\s*[^\]】]*)?)\s*[\]】]`)
+
+// owaspDefaultYear 未标注年份的OWASP分类按该版本处理
+const owaspDefaultYear = "2021"
+
+// listMarkerPattern 匹配行首的markdown列表符号和序号
+var listMarkerPattern = regexp.MustCompile(`^(?:[-*+>]\s+|\d+[.)、]\s*)`)
+
+// parseFindings 解析AI评审输出，生成规范化的问题列表；只有行首带有可识别等级标签的行才视为问题，
+// 这是评审结果的唯一解析入口，卡点、评论、通知和JSON结果都基于该列表
+func parseFindings(text string) []BlockIssue {
+	var issues []BlockIssue
+	for _, line := range strings.Split(text, "\n") {
+		if issue, ok := parseFinding(line); ok {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// parseFinding 解析单行问题，格式: [等级][规则ID（可选）] 文件名:行号 - 问题描述 - 修复建议
+func parseFinding(line string) (BlockIssue, bool) {
+	line = strings.TrimSpace(line)
+	line = listMarkerPattern.ReplaceAllString(line, "")
+	line = strings.TrimSpace(strings.TrimLeft(line, "*_`"))
+
+	// 行首的等级emoji，如「🔴 [block] ...」或「🔴 main.go:12 - ...」
+	emojiLevel := ""
+	for emoji, level := range levelEmojis {
+		if strings.HasPrefix(line, emoji) {
+			emojiLevel = level
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimPrefix(line, emoji), "\ufe0f"))
+			break
+		}
+	}
+
+	issue := BlockIssue{}
+	issue.CWE, issue.OWASP, line = extractSecurityTags(line)
+	matches := findingTagPattern.FindStringSubmatch(line)
+	if matches != nil {
+		level, ok := normalizeLevel(matches[1])
+		if !ok {
+			return BlockIssue{}, false
+		}
+		issue.Level = level
+		issue.RuleID = strings.TrimSpace(matches[2])
+		line = line[len(matches[0]):]
+	} else if emojiLevel != "" {
+		issue.Level = emojiLevel
+	} else {
+		return BlockIssue{}, false
+	}
+	line = strings.TrimSpace(strings.TrimLeft(line, "*_` "))
+	issue.Confidence, line = extractConfidence(line)
+
+	location := findingLocationPattern.FindStringSubmatch(line)
+	if location == nil {
+		// 只有行首emoji时要求带有位置，避免把模型结尾的「❌ 存在阻断问题」「⚠️ 注意」等总结语当作问题
+		if matches == nil {
+			return BlockIssue{}, false
+		}
+		// 无法解析位置时将整行作为问题描述
+		issue.File = "unknown"
+		issue.Line = "0"
+		issue.Issue = line
+		return issue, true
+	}
+	issue.File = strings.Trim(location[1], "*_` ")
+	issue.Line = location[2]
+	issue.Issue, issue.Suggestion = splitIssueSuggestion(location[3])
+	return issue, true
+}
+
+// extractConfidence 提取并去掉行尾的置信度，返回0~1之间的值；未给出或无效时返回0
+func extractConfidence(line string) (float64, string) {
+	matches := confidencePattern.FindStringSubmatchIndex(line)
+	if matches == nil {
+		return 0, line
+	}
+	value, err := strconv.ParseFloat(line[matches[2]:matches[3]], 64)
+	if err != nil {
+		return 0, line
+	}
+	if matches[4] >= 0 || value > 1 {
+		value /= 100
+	}
+	if value <= 0 || value > 1 {
+		return 0, strings.TrimSpace(line[:matches[0]])
+	}
+	return value, strings.TrimSpace(line[:matches[0]])
+}
+
+// extractSecurityTags 提取并去掉行中的CWE编号和OWASP分类标签，各取第一个，返回规范化的CWE-89和A03:2021
+func extractSecurityTags(line string) (string, string, string) {
+	var cwe, owasp string
+	line = securityTagPattern.ReplaceAllStringFunc(line, func(tag string) string {
+		matches := securityTagPattern.FindStringSubmatch(tag)
+		switch {
+		case matches[1] != "":
+			if cwe == "" {
+				cwe = strings.ToUpper(matches[1])
+			}
+		case owasp == "":
+			year := matches[3]
+			if year == "" {
+				year = owaspDefaultYear
+			}
+			owasp = strings.ToUpper(matches[2]) + ":" + year
+		}
+		return ""
+	})
+	return cwe, owasp, line
+}
+
+// normalizeLevel 规范化等级写法：忽略大小写和emoji，支持中文同义词
+func normalizeLevel(raw string) (string, bool) {
+	token := strings.ToLower(strings.TrimSpace(raw))
+	for emoji, level := range levelEmojis {
+		if strings.Contains(token, emoji) {
+			stripped := strings.TrimSpace(strings.ReplaceAll(strings.ReplaceAll(token, emoji, ""), "\ufe0f", ""))
+			if stripped == "" {
+				return level, true
+			}
+			token = stripped
+		}
+	}
+	token = strings.TrimSuffix(strings.TrimSpace(strings.Trim(token, "*_` ")), "级")
+	if level, ok := levelSynonyms[token]; ok {
+		return level, true
+	}
+	level, ok := levelSynonyms[token+"级"]
+	return level, ok
+}
+
+// splitIssueSuggestion 拆分「问题描述 - 修复建议」，优先按两侧带空格的分隔符拆分，允许描述中出现连字符
+func splitIssueSuggestion(text string) (string, string) {
+	for _, sep := range []string{" - ", " — ", " – "} {
+		if parts := strings.SplitN(text, sep, 2); len(parts) == 2 {
+			return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		}
+	}
+	if parts := strings.SplitN(text, "-", 2); len(parts) == 2 {
+		return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	}
+	return strings.TrimSpace(text), ""
+}
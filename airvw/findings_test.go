@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParseFindings 测试等级规范化（大小写、中文同义词、emoji）、CWE/OWASP标签以及非问题行（含只有emoji没有位置的总结语）的过滤
+func TestParseFindings(t *testing.T) {
+	text := strings.Join([]string{
+		"评审结果如下：",
+		"[block] main.go:12 - 未处理error - 增加错误处理",
+		"[Block][PAY-001] pay/card.go:8 - 日志输出完整卡号 - 使用maskCardNo脱敏",
+		"- [阻断] dao/user.go:30 - SQL拼接存在注入风险 - 使用参数化查询",
+		"2. 【高风险】 svc/order.go:45 - 循环内查询数据库 - 改为批量查询",
+		"🟡 util/str.go:3 - 函数过长 - 拆分函数",
+		"**[建议]** api/handler.go:100 - 变量名不规范 - 使用驼峰命名",
+		"[medium] cache/lru.go:20 - 缺少并发保护 - 使用sync.Mutex，见go-zero的实现",
+		"[high] 整体缺少单元测试",
+		"[block][CWE-89][A03:2021] dao/order.go:42 - 用户输入拼接进SQL - 使用参数化查询 (置信度:0.9)",
+		"[high][SEC-002]【cwe-918】[A10 - SSRF] api/proxy.go:15 - 请求用户可控URL - 校验目标地址",
+		"注意：描述中提到的[high]不应被识别为问题",
+		"[注意] 这不是问题等级",
+		"✅ 未发现任何问题",
+		"❌ 存在阻断问题，请尽快修复",
+		"⚠️ 注意：以上仅供参考",
+	}, "\n")
+
+	want := []BlockIssue{
+		{Level: LevelBlock, File: "main.go", Line: "12", Issue: "未处理error", Suggestion: "增加错误处理"},
+		{Level: LevelBlock, RuleID: "PAY-001", File: "pay/card.go", Line: "8", Issue: "日志输出完整卡号", Suggestion: "使用maskCardNo脱敏"},
+		{Level: LevelBlock, File: "dao/user.go", Line: "30", Issue: "SQL拼接存在注入风险", Suggestion: "使用参数化查询"},
+		{Level: LevelHigh, File: "svc/order.go", Line: "45", Issue: "循环内查询数据库", Suggestion: "改为批量查询"},
+		{Level: LevelMedium, File: "util/str.go", Line: "3", Issue: "函数过长", Suggestion: "拆分函数"},
+		{Level: LevelSuggest, File: "api/handler.go", Line: "100", Issue: "变量名不规范", Suggestion: "使用驼峰命名"},
+		{Level: LevelMedium, File: "cache/lru.go", Line: "20", Issue: "缺少并发保护", Suggestion: "使用sync.Mutex，见go-zero的实现"},
+		{Level: LevelHigh, File: "unknown", Line: "0", Issue: "整体缺少单元测试"},
+		{Level: LevelBlock, File: "dao/order.go", Line: "42", Issue: "用户输入拼接进SQL", Suggestion: "使用参数化查询", Confidence: 0.9, CWE: "CWE-89", OWASP: "A03:2021"},
+		{Level: LevelHigh, RuleID: "SEC-002", File: "api/proxy.go", Line: "15", Issue: "请求用户可控URL", Suggestion: "校验目标地址", CWE: "CWE-918", OWASP: "A10:2021"},
+	}
+	got := parseFindings(text)
+	if len(got) != len(want) {
+		t.Fatalf("解析出%d个问题，期望%d个：%+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("第%d个问题 = %+v，期望%+v", i+1, got[i], want[i])
+		}
+	}
+}
+
+// TestExtractConfidence 测试行尾置信度的各种写法
+func TestExtractConfidence(t *testing.T) {
+	cases := []struct {
+		line string
+		want float64
+		rest string
+	}{
+		{"main.go:12 - 未处理error - 增加错误处理 (置信度:0.85)", 0.85, "main.go:12 - 未处理error - 增加错误处理"},
+		{"main.go:12 - 未处理error - 增加错误处理 - confidence: 0.6", 0.6, "main.go:12 - 未处理error - 增加错误处理"},
+		{"main.go:12 - 未处理error（置信度 80%）", 0.8, "main.go:12 - 未处理error"},
+		{"main.go:12 - 未处理error [Confidence=90]", 0.9, "main.go:12 - 未处理error"},
+		{"main.go:12 - 未处理error (置信度:0)", 0, "main.go:12 - 未处理error"},
+		{"main.go:12 - 未处理error", 0, "main.go:12 - 未处理error"},
+	}
+	for _, c := range cases {
+		got, rest := extractConfidence(c.line)
+		if got != c.want || rest != c.rest {
+			t.Errorf("extractConfidence(%q) = %v, %q，期望%v, %q", c.line, got, rest, c.want, c.rest)
+		}
+	}
+}
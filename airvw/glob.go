@@ -0,0 +1,86 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// globCache 已编译的glob正则缓存
+var globCache sync.Map
+
+// matchGlob 判断路径是否匹配glob模式，语义与.gitignore一致：
+//   - `*`匹配除/外的任意字符，`?`匹配单个字符，`**`匹配任意层级目录
+//   - 不含/的模式匹配任意层级的文件名/目录名（如*.pb.go）
+//   - 以/开头的模式相对仓库根目录
+//   - 匹配目录时同时匹配目录下的所有文件（如vendor/）
+func matchGlob(pattern, filePath string) bool {
+	if pattern == "" {
+		return false
+	}
+	re, ok := globCache.Load(pattern)
+	if !ok {
+		compiled, err := regexp.Compile(globToRegexp(pattern))
+		if err != nil {
+			logDebug("⚠️【matchGlob】无效的glob模式：%s，%v\n", pattern, err)
+			return false
+		}
+		globCache.Store(pattern, compiled)
+		re = compiled
+	}
+	return re.(*regexp.Regexp).MatchString(strings.TrimPrefix(filePath, "/"))
+}
+
+// matchAnyGlob 判断路径是否匹配任一glob模式
+func matchAnyGlob(patterns []string, filePath string) bool {
+	for _, pattern := range patterns {
+		if matchGlob(pattern, filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp 将glob模式转换为正则表达式
+func globToRegexp(pattern string) string {
+	pattern = strings.TrimSuffix(pattern, "/")
+	if strings.HasPrefix(pattern, "/") {
+		pattern = strings.TrimPrefix(pattern, "/")
+	} else if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end <= 1 {
+				re.WriteString(`\[`)
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + class + "]")
+			i += end
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("(?:/.*)?$")
+	return re.String()
+}
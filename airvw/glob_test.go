@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+// TestMatchGlob 测试gitignore风格的glob匹配
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*.pb.go", "api/v1/user.pb.go", true},
+		{"*.pb.go", "api/v1/user.go", false},
+		{"vendor/", "vendor/github.com/x/y.go", true},
+		{"vendor", "pkg/vendor/y.go", true},
+		{"/vendor", "pkg/vendor/y.go", false},
+		{"payment/**", "payment/card/log.go", true},
+		{"payment/**", "order/payment.go", false},
+		{"**/mocks/*.go", "internal/user/mocks/repo.go", true},
+		{"**/mocks/*.go", "mocks/repo.go", true},
+		{"db/migrations/*.sql", "db/migrations/001_init.sql", true},
+		{"db/migrations/*.sql", "db/migrations/old/001_init.sql", false},
+		{"src/**/*.ts", "src/a/b/c.ts", true},
+		{"src/**/*.ts", "src/c.ts", true},
+		{"file?.go", "file1.go", true},
+		{"[abc].go", "b.go", true},
+		{"[!abc].go", "b.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"|"+tt.path, func(t *testing.T) {
+			if got := matchGlob(tt.pattern, tt.path); got != tt.want {
+				t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
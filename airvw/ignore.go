@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// defaultIgnoreFile 默认的忽略规则文件（gitignore语法，位于仓库根目录）
+const defaultIgnoreFile = ".airvwignore"
+
+// builtinIgnorePatterns 内置忽略的生成代码/第三方代码路径
+var builtinIgnorePatterns = []string{
+	"*.pb.go", "*.pb.gw.go", "*_grpc.pb.go", "*.pb.validate.go",
+	"*_gen.go", "*.gen.go", "zz_generated*.go", "wire_gen.go",
+	"mock_*.go", "*_mock.go", "mocks/",
+	"vendor/", "node_modules/", "Pods/", "third_party/",
+	"*.min.js", "*.bundle.js",
+	"*_pb2.py", "*_pb2_grpc.py",
+}
+
+// generatedMarkerPattern 生成代码标识：Go规范的「Code generated ... DO NOT EDIT.」以及通用的@generated
+var generatedMarkerPattern = regexp.MustCompile(`(?m)^[+ ]\s*(?://|#|/\*+|\*|<!--|--)?\s*(?:Code generated .*DO NOT EDIT|@generated\b)`)
+
+// generatedHeaderPattern 工作区文件头部的生成代码标识，与generatedMarkerPattern相同但不带diff行前缀
+var generatedHeaderPattern = regexp.MustCompile(`^\s*(?://|#|/\*+|\*|<!--|--)?\s*(?:Code generated .*DO NOT EDIT|@generated\b)`)
+
+// generatedHeaderLines 读取工作区文件头部的行数，生成代码标识按约定位于文件开头
+const generatedHeaderLines = 30
+
+// PathFilterConfig 评审路径过滤配置
+type PathFilterConfig struct {
+	Include         []string `json:"include"`          // 仅评审匹配的路径（glob），为空表示全部
+	Exclude         []string `json:"exclude"`          // 不评审的路径（glob）
+	IgnoreFile      string   `json:"ignore_file"`      // 忽略规则文件，默认.airvwignore
+	DisableDefaults bool     `json:"disable_defaults"` // 是否关闭内置的生成代码/第三方代码忽略规则
+}
+
+// ignoreRule 单条忽略规则
+type ignoreRule struct {
+	pattern string
+	negate  bool   // 以!开头，重新纳入评审
+	source  string // 规则来源，用于调试输出
+}
+
+// PathFilter 评审路径过滤器：include → 忽略规则（内置 → 配置exclude → .airvwignore，后者优先）→ 生成代码标识
+type PathFilter struct {
+	include        []string
+	rules          []ignoreRule
+	checkGenerated bool
+}
+
+// newPathFilter 根据配置创建路径过滤器；未显式指定且默认忽略文件不存在时忽略
+func newPathFilter(conf PathFilterConfig) (*PathFilter, error) {
+	filter := &PathFilter{include: conf.Include, checkGenerated: !conf.DisableDefaults}
+	if !conf.DisableDefaults {
+		for _, pattern := range builtinIgnorePatterns {
+			filter.rules = append(filter.rules, ignoreRule{pattern: pattern, source: "内置规则"})
+		}
+	}
+	for _, pattern := range conf.Exclude {
+		filter.rules = append(filter.rules, parseIgnoreLine(pattern, "配置exclude"))
+	}
+
+	ignoreFile := conf.IgnoreFile
+	explicit := ignoreFile != ""
+	if !explicit {
+		ignoreFile = defaultIgnoreFile
+	}
+	rules, err := loadIgnoreFile(ignoreFile)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return filter, nil
+		}
+		return nil, fmt.Errorf("读取忽略规则文件%s失败：%w", ignoreFile, err)
+	}
+	logDebug("✅【newPathFilter】已加载忽略规则文件%s，共%d条规则\n", ignoreFile, len(rules))
+	filter.rules = append(filter.rules, rules...)
+	return filter, nil
+}
+
+// loadIgnoreFile 按gitignore语法读取忽略规则：#开头为注释，!开头为取反，\#、\!转义
+func loadIgnoreFile(path string) ([]ignoreRule, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(file)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rules = append(rules, parseIgnoreLine(line, fmt.Sprintf("%s:%d", path, lineNo)))
+	}
+	return rules, scanner.Err()
+}
+
+// parseIgnoreLine 解析单条忽略规则
+func parseIgnoreLine(line, source string) ignoreRule {
+	rule := ignoreRule{source: source}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	rule.pattern = line
+	return rule
+}
+
+// Skip 判断文件是否跳过评审，返回跳过原因
+func (f *PathFilter) Skip(item DiffItem) (string, bool) {
+	filePath := item.NewPath
+	if filePath == "" {
+		filePath = item.OldPath
+	}
+
+	if len(f.include) > 0 && !matchAnyGlob(f.include, filePath) {
+		return "不在include范围内", true
+	}
+
+	// 与gitignore一致，最后一条命中的规则生效
+	var matched *ignoreRule
+	for i := range f.rules {
+		if matchGlob(f.rules[i].pattern, filePath) {
+			matched = &f.rules[i]
+		}
+	}
+	if matched != nil {
+		if !matched.negate {
+			return fmt.Sprintf("命中忽略规则%s（%s）", matched.pattern, matched.source), true
+		}
+		// 显式取反的文件即使带有生成代码标识也纳入评审
+		return "", false
+	}
+
+	// 修改已有生成文件时diff的hunk不一定包含文件头，未命中时再读取工作区文件的头部
+	if f.checkGenerated && (generatedMarkerPattern.MatchString(item.Diff) || hasGeneratedHeader(item.NewPath)) {
+		return "包含生成代码标识（Code generated ... DO NOT EDIT / @generated）", true
+	}
+	return "", false
+}
+
+// hasGeneratedHeader 判断工作区文件头部是否包含生成代码标识，文件不存在（如已删除）时返回false
+func hasGeneratedHeader(filePath string) bool {
+	if filePath == "" {
+		return false
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for n := 0; n < generatedHeaderLines && scanner.Scan(); n++ {
+		if generatedHeaderPattern.MatchString(scanner.Text()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply 过滤变更文件列表，并在调试模式下输出每个跳过文件的原因
+func (f *PathFilter) Apply(items []DiffItem) []DiffItem {
+	var kept []DiffItem
+	for _, item := range items {
+		if reason, skip := f.Skip(item); skip {
+			logDebug("⏭️【PathFilter】跳过文件%s：%s\n", firstNonEmpty(item.NewPath, item.OldPath), reason)
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
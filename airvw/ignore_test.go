@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestPathFilterSkip 测试include/exclude、.airvwignore取反以及生成代码标识
+func TestPathFilterSkip(t *testing.T) {
+	// 工作区中已有的生成文件，修改时diff的hunk不包含文件头
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "internal/api"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	header := "// Code generated by stringer -type=Status; DO NOT EDIT.\n\npackage api\n\nfunc (i Status) String() string {\n"
+	if err := os.WriteFile(filepath.Join(root, "internal/api/status_string.go"), []byte(header), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(root); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	ignoreFile := filepath.Join(t.TempDir(), ".airvwignore")
+	content := "# 数据库迁移无需评审\ndb/migrations/\n!internal/mocks/handwritten.go\n"
+	if err := os.WriteFile(ignoreFile, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	filter, err := newPathFilter(PathFilterConfig{
+		Include:    []string{"internal/", "cmd/", "db/"},
+		Exclude:    []string{"*_test.go"},
+		IgnoreFile: ignoreFile,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	generated := "@@ -0,0 +1,3 @@\n+// Code generated by protoc-gen-go. DO NOT EDIT.\n+\n+package api\n"
+	tests := []struct {
+		item DiffItem
+		want bool
+	}{
+		{DiffItem{NewPath: "internal/user/service.go", Diff: "@@ -1 +1 @@\n+package user\n"}, false},
+		{DiffItem{NewPath: "pkg/util/str.go"}, true},
+		{DiffItem{NewPath: "internal/api/user.pb.go"}, true},
+		{DiffItem{NewPath: "internal/user/service_test.go"}, true},
+		{DiffItem{NewPath: "db/migrations/001_init.go"}, true},
+		{DiffItem{NewPath: "internal/mocks/repo.go"}, true},
+		{DiffItem{NewPath: "internal/mocks/handwritten.go", Diff: generated}, false},
+		{DiffItem{NewPath: "internal/api/enum.go", Diff: generated}, true},
+		{DiffItem{NewPath: "internal/api/status_string.go", Diff: "@@ -20,3 +20,4 @@\n \tcase 1:\n+\tcase 2:\n"}, true},
+		{DiffItem{OldPath: "cmd/vendor/a.go", DeletedFile: true}, true},
+	}
+	for _, tt := range tests {
+		reason, got := filter.Skip(tt.item)
+		if got != tt.want {
+			t.Errorf("Skip(%s) = %v（%s），期望%v", firstNonEmpty(tt.item.NewPath, tt.item.OldPath), got, reason, tt.want)
+		}
+	}
+}
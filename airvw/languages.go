@@ -0,0 +1,567 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// defaultLanguage 未指定或无法识别--language时使用的语言
+const defaultLanguage = "golang"
+
+// 静态检查输出的解析方式
+const (
+	LintParserRaw   = "raw"   // 原样输出（逐个文件执行时的默认值）
+	LintParserLines = "lines" // 只保留提到该文件的行（工程级执行时的默认值）
+	LintParserGCC   = "gcc"   // 只保留「文件:行:列: 描述」格式中该文件的行，输出为「行:列: 描述」
+)
+
+// gccDiagnosticPattern 匹配gcc风格的诊断输出：文件:行[:列]: 描述
+var gccDiagnosticPattern = regexp.MustCompile(`^(.+?):(\d+)(?::(\d+))?:\s*(.*)$`)
+
+// LinterSpec 静态检查工具
+type LinterSpec struct {
+	Name         string   `json:"name"`               // 工具名，用于展示，默认为command
+	Command      string   `json:"command"`            // 可执行文件，优先使用仓库node_modules/.bin中的版本
+	Args         []string `json:"args"`               // 参数，{file}替换为待检查文件；不包含{file}时在仓库根目录执行一次，按文件路径归类输出
+	FindingCodes []int    `json:"finding_exit_codes"` // 发现问题时的退出码，视为正常执行
+	Parser       string   `json:"parser"`             // 输出解析方式：raw/lines/gcc
+	Files        []string `json:"files"`              // 只检查匹配的文件（glob），为空表示该语言的所有文件
+	Optional     bool     `json:"optional"`           // 可选工具：未安装时直接跳过，不在检查结果中注明
+}
+
+// LanguageSpec 语言声明：文件后缀、prompt模板和静态检查工具，内置语言和配置文件中声明的语言都通过它实现ReviewProcess
+type LanguageSpec struct {
+	Name        string       `json:"name"`         // 语言标识，同时作为默认的prompt模板名称
+	DisplayName string       `json:"display_name"` // 展示名，默认为name
+	Aliases     []string     `json:"aliases"`      // --language可使用的其他标识
+	Extensions  []string     `json:"extensions"`   // 文件后缀
+	Files       []string     `json:"files"`        // 文件路径模式（glob），用于没有固定后缀的文件，如Dockerfile、.gitlab-ci.yml
+	Markers     []string     `json:"markers"`      // 按后缀匹配的文件还需包含任一标记才评审，如Kubernetes清单的apiVersion:
+	Prompt      string       `json:"prompt"`       // prompt模板名称（prompts目录或--prompt-dir下的<名称>.tmpl），默认为name，不存在时使用通用模板
+	Dimensions  string       `json:"dimensions"`   // 使用通用模板时的评审维度
+	Linters     []LinterSpec `json:"linters"`      // 静态检查工具，按顺序执行，缺失的工具跳过
+
+	SecurityLinters []LinterSpec `json:"security_linters"` // --profile security时追加执行的安全扫描工具
+	TestFiles       []string     `json:"test_files"`       // 测试文件命名规则，{name}为被测文件去掉后缀的文件名，如{name}_test.go；为空时不检查测试缺口
+
+	// lint 内置语言的定制检查流程（如TypeScript的工程级类型检查），先于Linters执行，返回文件路径 -> 检查结果段落
+	lint func(repoPath string, diffFiles map[string]string) map[string][]string
+	// scan 内置语言的确定性检查（如依赖的已知漏洞和许可证），发现的问题不经过模型直接输出
+	scan func(repoPath string, diffFiles map[string]string, locale string) ([]BlockIssue, error)
+}
+
+// builtinLanguages 内置语言
+var builtinLanguages = []*LanguageSpec{
+	{
+		Name: "golang", DisplayName: "Go", Aliases: []string{"go"},
+		Extensions: []string{".go"},
+		TestFiles:  []string{"{name}_test.go"},
+		Linters: []LinterSpec{
+			{Name: "golangci-lint", Command: "golangci-lint", Args: []string{"run", "--new-from-rev=origin/main", "{file}"}, FindingCodes: []int{1}},
+		},
+		SecurityLinters: []LinterSpec{
+			{Name: "gosec", Command: "gosec", Args: []string{"-fmt=golint", "-quiet", "./..."}, FindingCodes: []int{1}, Parser: LintParserGCC, Optional: true},
+		},
+	},
+	{
+		Name: "java", DisplayName: "Java",
+		Extensions: []string{".java"},
+		TestFiles:  []string{"{name}Test.java", "Test{name}.java", "{name}Tests.java", "{name}IT.java"},
+		Linters: []LinterSpec{
+			{Name: "checkstyle", Command: "checkstyle", Args: []string{"-c", "/google_checks.xml", "{file}"}},
+		},
+	},
+	{
+		Name: "python", DisplayName: "Python",
+		Extensions: []string{".py"},
+		TestFiles:  []string{"test_{name}.py", "{name}_test.py"},
+		Linters: []LinterSpec{
+			{Name: "flake8", Command: "flake8", Args: []string{"{file}"}, FindingCodes: []int{1}},
+		},
+		SecurityLinters: []LinterSpec{
+			{Name: "bandit", Command: "bandit", Args: []string{"-q", "-f", "custom", "--msg-template", "{line}: {test_id}[{severity}] {msg}", "{file}"}, FindingCodes: []int{1}, Optional: true},
+		},
+	},
+	{
+		Name: "javascript", DisplayName: "JavaScript", Aliases: []string{"js"},
+		Extensions: []string{".js", ".jsx", ".mjs", ".cjs"},
+		TestFiles:  []string{"{name}.test.js", "{name}.spec.js", "{name}.test.jsx", "{name}.spec.jsx", "__tests__/{name}.js", "__tests__/{name}.jsx"},
+		Linters: []LinterSpec{
+			{Name: "eslint", Command: "eslint", Args: []string{"{file}"}, FindingCodes: []int{1}},
+		},
+	},
+	{
+		// .vue单文件组件只归属TypeScript（通常使用<script lang="ts">，且需要vue-tsc做类型检查），避免同时以两种语言评审时重复
+		Name: "typescript", DisplayName: "TypeScript", Aliases: []string{"ts", "tsx"},
+		Extensions: []string{".ts", ".tsx", ".mts", ".cts", ".vue"},
+		TestFiles:  []string{"{name}.test.ts", "{name}.spec.ts", "{name}.test.tsx", "{name}.spec.tsx", "__tests__/{name}.ts", "__tests__/{name}.tsx"},
+		lint:       runTypeScriptLint,
+	},
+	{
+		Name: "swift", DisplayName: "Swift",
+		Extensions: []string{".swift"},
+		TestFiles:  []string{"{name}Tests.swift", "{name}Test.swift"},
+		Linters: []LinterSpec{
+			{Name: "swiftlint", Command: "swiftlint", Args: []string{"lint", "{file}"}, FindingCodes: []int{2}},
+		},
+	},
+	{
+		Name: "kotlin", DisplayName: "Kotlin", Aliases: []string{"kt"},
+		Extensions: []string{".kt"},
+		TestFiles:  []string{"{name}Test.kt", "{name}Tests.kt"},
+		Linters: []LinterSpec{
+			{Name: "ktlint", Command: "ktlint", Args: []string{"{file}"}, FindingCodes: []int{1}},
+		},
+	},
+	{
+		Name: "cpp", DisplayName: "C/C++", Aliases: []string{"c", "c++", "cxx"},
+		Extensions: []string{".c", ".h", ".cc", ".cpp", ".cxx", ".hh", ".hpp", ".hxx"},
+		TestFiles:  []string{"{name}_test.cc", "{name}_test.cpp", "{name}_unittest.cc", "{name}_test.c"},
+		Linters: []LinterSpec{
+			{Name: "clang-tidy", Command: "clang-tidy", Args: []string{"--quiet", "{file}"}, FindingCodes: []int{1}},
+			{Name: "cppcheck", Command: "cppcheck", Args: []string{"--enable=warning,style,performance,portability", "--quiet", "--template=gcc", "{file}"}},
+		},
+	},
+	{
+		Name: "rust", DisplayName: "Rust", Aliases: []string{"rs"},
+		Extensions: []string{".rs"},
+		Linters: []LinterSpec{
+			{Name: "clippy", Command: "cargo", Args: []string{"clippy", "--quiet", "--message-format=short"}, FindingCodes: []int{101}},
+		},
+	},
+	{
+		Name: "php", DisplayName: "PHP",
+		Extensions: []string{".php"},
+		TestFiles:  []string{"{name}Test.php"},
+		Linters: []LinterSpec{
+			{Name: "phpstan", Command: "phpstan", Args: []string{"analyse", "--no-progress", "--error-format=raw", "{file}"}, FindingCodes: []int{1}},
+		},
+	},
+	{
+		Name: "csharp", DisplayName: "C#", Aliases: []string{"cs", "c#", "dotnet"},
+		Extensions: []string{".cs"},
+		TestFiles:  []string{"{name}Tests.cs", "{name}Test.cs"},
+		Linters: []LinterSpec{
+			{Name: "dotnet format analyzers", Command: "dotnet", Args: []string{"format", "analyzers", "--verify-no-changes", "--severity", "warn", "--include", "{file}"}, FindingCodes: []int{2}},
+		},
+	},
+	{
+		Name: "ruby", DisplayName: "Ruby", Aliases: []string{"rb"},
+		Extensions: []string{".rb", ".rake", ".gemspec"},
+		TestFiles:  []string{"{name}_spec.rb", "{name}_test.rb", "test_{name}.rb"},
+		Linters: []LinterSpec{
+			{Name: "rubocop", Command: "rubocop", Args: []string{"--format", "emacs", "{file}"}, FindingCodes: []int{1}},
+		},
+	},
+	{
+		Name: "dart", DisplayName: "Dart/Flutter", Aliases: []string{"flutter"},
+		Extensions: []string{".dart"},
+		TestFiles:  []string{"{name}_test.dart"},
+		Linters: []LinterSpec{
+			{Name: "dart analyze", Command: "dart", Args: []string{"analyze", "{file}"}, FindingCodes: []int{1, 2, 3}},
+		},
+	},
+	{
+		Name: "sql", DisplayName: "SQL",
+		Extensions: []string{".sql"},
+		Linters: []LinterSpec{
+			{Name: "sqlfluff", Command: "sqlfluff", Args: []string{"lint", "{file}"}, FindingCodes: []int{1}},
+		},
+	},
+	{
+		Name: "shell", DisplayName: "Shell", Aliases: []string{"sh", "bash"},
+		Extensions: []string{".sh", ".bash", ".ksh"},
+		Linters: []LinterSpec{
+			{Name: "shellcheck", Command: "shellcheck", Args: []string{"--format", "gcc", "{file}"}, FindingCodes: []int{1}},
+		},
+	},
+	{
+		Name: "dockerfile", DisplayName: "Dockerfile", Aliases: []string{"docker", "containerfile"},
+		Extensions: []string{".dockerfile"},
+		Files:      []string{"Dockerfile", "Dockerfile.*", "*.Dockerfile", "Containerfile"},
+		Linters: []LinterSpec{
+			{Name: "hadolint", Command: "hadolint", Args: []string{"--no-color", "{file}"}, FindingCodes: []int{1}},
+		},
+	},
+	{
+		// Helm模板包含模板语法，kubeconform只校验普通清单
+		Name: "kubernetes", DisplayName: "Kubernetes/Helm", Aliases: []string{"k8s", "helm"},
+		Extensions: []string{".yaml", ".yml"},
+		Markers:    []string{"apiVersion:"},
+		Files:      []string{"Chart.yaml", "values*.yaml", "kustomization.yaml", "**/templates/*.tpl"},
+		Linters: []LinterSpec{
+			{Name: "kubeconform", Command: "kubeconform", Args: []string{"-summary", "-ignore-missing-schemas", "{file}"}, FindingCodes: []int{1}},
+			{Name: "kube-linter", Command: "kube-linter", Args: []string{"lint", "{file}"}, FindingCodes: []int{1}},
+		},
+	},
+	{
+		Name: "terraform", DisplayName: "Terraform", Aliases: []string{"tf", "hcl"},
+		Extensions: []string{".tf", ".tfvars", ".hcl"},
+		Linters: []LinterSpec{
+			{Name: "tflint", Command: "tflint", Args: []string{"--recursive", "--format", "compact"}, FindingCodes: []int{2}},
+			{Name: "tfsec", Command: "tfsec", Args: []string{".", "--format", "csv", "--no-color"}, FindingCodes: []int{1}},
+		},
+	},
+	{
+		Name: "ci", DisplayName: "CI流水线", Aliases: []string{"pipeline", "github-actions", "gitlab-ci"},
+		Files: []string{
+			".gitlab-ci.yml", ".gitlab-ci/**/*.yml", ".github/workflows/*.yml", ".github/workflows/*.yaml", ".github/actions/**/action.yml",
+			".circleci/config.yml", ".drone.yml", ".travis.yml", "azure-pipelines.yml", "bitbucket-pipelines.yml", "Jenkinsfile", ".workflow/*.yml",
+		},
+		Linters: []LinterSpec{
+			{Name: "actionlint", Command: "actionlint", Args: []string{"-no-color", "{file}"}, FindingCodes: []int{1}, Files: []string{".github/workflows/*.yml", ".github/workflows/*.yaml"}},
+		},
+	},
+	{
+		// 依赖清单与锁文件：解析依赖变更并检查已知漏洞和许可证，由模型评估主版本升级等风险
+		Name: "dependencies", DisplayName: "依赖清单", Aliases: []string{"deps", "dependency", "sca"},
+		Files: dependencyManifestFiles(),
+		lint:  runDependencyLint,
+		scan:  scanDependencies,
+	},
+}
+
+// languageRegistry 语言注册表：内置语言在前，配置文件中声明的语言在后，查找时后注册的优先
+var languageRegistry = append([]*LanguageSpec{}, builtinLanguages...)
+
+// registerLanguages 注册配置文件中声明的语言，与内置语言同名或同别名时覆盖内置语言
+func registerLanguages(specs []LanguageSpec) {
+	for i := range specs {
+		spec := specs[i]
+		for j, ext := range spec.Extensions {
+			spec.Extensions[j] = strings.ToLower(ext)
+		}
+		logDebug("ℹ️【registerLanguages】注册语言：%s（%s）\n", spec.Name, strings.Join(spec.Extensions, "/"))
+		languageRegistry = append(languageRegistry, &spec)
+	}
+}
+
+// lookupLanguage 按语言标识或别名（忽略大小写）查找语言，不存在时返回nil
+func lookupLanguage(language string) *LanguageSpec {
+	language = strings.ToLower(strings.TrimSpace(language))
+	for i := len(languageRegistry) - 1; i >= 0; i-- {
+		spec := languageRegistry[i]
+		if strings.ToLower(spec.Name) == language {
+			return spec
+		}
+		for _, alias := range spec.Aliases {
+			if strings.ToLower(alias) == language {
+				return spec
+			}
+		}
+	}
+	return nil
+}
+
+// languageNames 已注册的语言标识，按注册顺序去重
+func languageNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, spec := range languageRegistry {
+		if !seen[spec.Name] {
+			seen[spec.Name] = true
+			names = append(names, spec.Name)
+		}
+	}
+	return names
+}
+
+// validateLanguages 校验配置文件中声明的语言
+func validateLanguages(specs []LanguageSpec) error {
+	for i, spec := range specs {
+		if strings.TrimSpace(spec.Name) == "" {
+			return fmt.Errorf("languages[%d]缺少name", i)
+		}
+		if len(spec.Extensions) == 0 && len(spec.Files) == 0 {
+			return fmt.Errorf("语言%s缺少extensions或files", spec.Name)
+		}
+		if err := validateLinters(spec.Name, "linters", spec.Linters); err != nil {
+			return err
+		}
+		if err := validateLinters(spec.Name, "security_linters", spec.SecurityLinters); err != nil {
+			return err
+		}
+		for j, testFile := range spec.TestFiles {
+			if !strings.Contains(testFile, "{name}") {
+				return fmt.Errorf("语言%s的test_files[%d]缺少{name}占位符：%s", spec.Name, j, testFile)
+			}
+		}
+	}
+	return nil
+}
+
+// validateLinters 校验语言声明中的静态检查工具
+func validateLinters(language, field string, linters []LinterSpec) error {
+	for j, linter := range linters {
+		if linter.Command == "" {
+			return fmt.Errorf("语言%s的%s[%d]缺少command", language, field, j)
+		}
+		switch linter.Parser {
+		case "", LintParserRaw, LintParserLines, LintParserGCC:
+		default:
+			return fmt.Errorf("语言%s的%s[%d].parser无效：%s（可选：raw/lines/gcc）", language, field, j, linter.Parser)
+		}
+	}
+	return nil
+}
+
+// GetReviewProcess 根据语言获取对应的评审流程实现，未指定或无法识别的语言按Go评审
+func GetReviewProcess(language string) ReviewProcess {
+	if spec := lookupLanguage(language); spec != nil {
+		return spec
+	}
+	return lookupLanguage(defaultLanguage)
+}
+
+// languageDisplayName 评审语言的展示名
+func languageDisplayName(language string) string {
+	spec := lookupLanguage(language)
+	if spec == nil {
+		spec = lookupLanguage(defaultLanguage)
+	}
+	return firstNonEmpty(spec.DisplayName, spec.Name)
+}
+
+// languageDimensions 语言声明中的评审维度，供通用prompt模板使用
+func languageDimensions(process ReviewProcess) string {
+	if spec, ok := process.(*LanguageSpec); ok {
+		return spec.Dimensions
+	}
+	return ""
+}
+
+// scanFindings 执行内置语言的确定性检查，没有定制检查的语言返回空
+func scanFindings(process ReviewProcess, repoPath string, diffFiles map[string]string, locale string) ([]BlockIssue, error) {
+	if spec, ok := process.(*LanguageSpec); ok && spec.scan != nil {
+		return spec.scan(repoPath, diffFiles, locale)
+	}
+	return nil, nil
+}
+
+func (l *LanguageSpec) GetFileExtension() string {
+	return strings.Join(l.Extensions, "/")
+}
+
+// GetPromptName 优先使用声明的prompt模板，内置模板和覆盖目录中都不存在时使用通用模板
+func (l *LanguageSpec) GetPromptName() string {
+	name := firstNonEmpty(l.Prompt, l.Name)
+	if !prompts.Has(name) {
+		logDebug("ℹ️【LanguageSpec】未找到%s的prompt模板，使用通用模板\n", name)
+		return genericPromptName
+	}
+	return name
+}
+
+// RunLint 依次执行定制检查流程和声明的静态检查工具，未安装的工具跳过并在结果中注明（可选工具不注明）
+func (l *LanguageSpec) RunLint(repoPath string, diffFiles map[string]string) map[string]string {
+	sections := make(map[string][]string)
+	if l.lint != nil {
+		sections = l.lint(repoPath, diffFiles)
+	}
+
+	logDebugln("\n=====================================")
+	logDebug("【RunLint】开始执行%s静态检查\n", languageDisplayName(l.Name))
+	logDebug("  - 仓库路径：%s\n", repoPath)
+	logDebug("  - 待检查文件数：%d\n", len(diffFiles))
+	logDebugln("=====================================")
+
+	for _, linter := range l.Linters {
+		name := firstNonEmpty(linter.Name, linter.Command)
+		bin := findTool(repoPath, linter.Command)
+		if bin == "" {
+			logDebug("⚠️【RunLint】未检测到%s，跳过该项检查\n", name)
+			if linter.Optional {
+				continue
+			}
+			for file := range diffFiles {
+				if linter.applies(file) {
+					sections[file] = append(sections[file], fmt.Sprintf("%s未执行：缺少%s环境", name, linter.Command))
+				}
+			}
+			continue
+		}
+
+		if !linter.perFile() {
+			// 工程级检查只执行一次，按文件路径归类输出
+			output, err := linter.run(bin, repoPath, "")
+			for file := range diffFiles {
+				if !linter.applies(file) {
+					continue
+				}
+				if err != nil {
+					sections[file] = append(sections[file], fmt.Sprintf("%s执行失败：%s", name, err))
+				} else if out := linter.parse(output, file); out != "" {
+					sections[file] = append(sections[file], name+"：\n"+out)
+				}
+			}
+			continue
+		}
+
+		for file := range diffFiles {
+			if !linter.applies(file) {
+				continue
+			}
+			logDebug("ℹ️【RunLint】%s检查文件：%s\n", name, file)
+			output, err := linter.run(bin, repoPath, file)
+			if err != nil {
+				logDebug("⚠️【RunLint】文件%s %s执行失败：%v\n", file, name, err)
+				sections[file] = append(sections[file], fmt.Sprintf("%s执行失败：%s", name, err))
+			} else if out := linter.parse(output, file); out != "" {
+				sections[file] = append(sections[file], name+"：\n"+out)
+			}
+		}
+	}
+
+	lintResults := make(map[string]string)
+	for file := range diffFiles {
+		if len(sections[file]) == 0 {
+			logDebug("✅【RunLint】文件%s未发现违规问题\n", file)
+			lintResults[file] = "【规则检查】未发现违规问题"
+			continue
+		}
+		logDebug("⚠️【RunLint】文件%s的检查结果：%s\n", file, strings.Join(sections[file], "\n"))
+		lintResults[file] = "【规则检查】" + strings.Join(sections[file], "\n")
+	}
+	return lintResults
+}
+
+// FilterFiles 保留新增/修改以及重命名且有改动的文件，按路径模式或后缀（忽略大小写）匹配语言
+func (l *LanguageSpec) FilterFiles(diffItems []DiffItem) map[string]string {
+	diffMap := make(map[string]string)
+	for _, diffItem := range diffItems {
+		// 跳过二进制文件
+		if diffItem.Binary {
+			logDebug("ℹ️【GetMRDiff】跳过二进制文件：%s\n", diffItem.NewPath)
+			continue
+		}
+
+		// 确定文件路径（兼容重命名/删除场景）
+		filePath := diffItem.NewPath
+		if filePath == "" {
+			filePath = diffItem.OldPath
+		}
+
+		// 确定文件状态
+		var status string
+		if diffItem.NewFile {
+			status = "added"
+		} else if diffItem.DeletedFile {
+			status = "removed"
+		} else if diffItem.RenamedFile {
+			status = "renamed"
+		} else {
+			status = "modified"
+		}
+
+		if (status == "added" || status == "modified" || (status == "renamed" && diffItem.Diff != "")) && l.matches(filePath, diffItem.Diff) {
+			diffMap[filePath] = diffItem.Diff
+			logDebug("✅【GetMRDiff】检测到需评审文件：%s（状态：%s）\n", filePath, status)
+		}
+	}
+	return diffMap
+}
+
+// matches 判断文件是否属于该语言：命中files中的路径模式，或后缀匹配且包含任一标记（未声明markers时不检查）
+func (l *LanguageSpec) matches(filePath, diff string) bool {
+	if matchAnyGlob(l.Files, filePath) {
+		return true
+	}
+	if !hasAnySuffix(strings.ToLower(filePath), l.Extensions) {
+		return false
+	}
+	return len(l.Markers) == 0 || containsMarker(filePath, diff, l.Markers)
+}
+
+// containsMarker 判断diff或工作区中的文件内容是否包含任一标记，diff中只有部分内容，未命中时再读取工作区文件
+func containsMarker(filePath, diff string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.Contains(diff, marker) {
+			return true
+		}
+	}
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return false
+	}
+	for _, marker := range markers {
+		if strings.Contains(string(data), marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// applies 判断检查工具是否适用于该文件
+func (l LinterSpec) applies(file string) bool {
+	return len(l.Files) == 0 || matchAnyGlob(l.Files, file)
+}
+
+// perFile 参数中包含{file}时逐个文件执行
+func (l LinterSpec) perFile() bool {
+	for _, arg := range l.Args {
+		if strings.Contains(arg, "{file}") {
+			return true
+		}
+	}
+	return false
+}
+
+// run 在仓库根目录执行检查工具，退出码为0或FindingCodes中的值时视为正常执行
+func (l LinterSpec) run(bin, repoPath, file string) (string, error) {
+	args := make([]string, len(l.Args))
+	for i, arg := range l.Args {
+		args[i] = strings.ReplaceAll(arg, "{file}", file)
+	}
+	cmd := exec.Command(bin, args...)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	out := strings.TrimSpace(string(output))
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		for _, code := range l.FindingCodes {
+			if exitErr.ExitCode() == code {
+				return out, nil
+			}
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s，输出：%s", err.Error(), out)
+	}
+	return out, nil
+}
+
+// parse 按解析方式提取与文件相关的输出
+func (l LinterSpec) parse(output, file string) string {
+	parser := l.Parser
+	if parser == "" {
+		parser = LintParserRaw
+		if !l.perFile() {
+			parser = LintParserLines
+		}
+	}
+
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		switch parser {
+		case LintParserLines:
+			if strings.Contains(line, file) {
+				lines = append(lines, line)
+			}
+		case LintParserGCC:
+			matches := gccDiagnosticPattern.FindStringSubmatch(strings.TrimSpace(line))
+			if matches == nil || !strings.HasSuffix(strings.TrimPrefix(matches[1], "./"), file) {
+				continue
+			}
+			location := matches[2]
+			if matches[3] != "" {
+				location += ":" + matches[3]
+			}
+			lines = append(lines, location+": "+matches[4])
+		default:
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestBuiltinLanguages 测试内置语言的别名、文件后缀和prompt模板
+func TestBuiltinLanguages(t *testing.T) {
+	cases := map[string]string{
+		"c++": "lib/buf.cpp", "rs": "src/main.rs", "php": "app/User.php", "c#": "Api/Startup.cs",
+		"rb": "app/models/user.rb", "go": "main.go", "kt": "App.kt", "flutter": "lib/main.dart", "sql": "db/V1__init.sql", "bash": "deploy.sh",
+	}
+	for language, file := range cases {
+		process := GetReviewProcess(language)
+		spec := lookupLanguage(language)
+		if spec == nil || process != ReviewProcess(spec) {
+			t.Errorf("GetReviewProcess(%q) = %v，期望注册表中的语言", language, process)
+			continue
+		}
+		if got := process.FilterFiles([]DiffItem{{NewPath: file, Diff: "+"}, {NewPath: "README.md", Diff: "+"}}); len(got) != 1 || got[file] == "" {
+			t.Errorf("%s FilterFiles() = %v，期望只包含%s", language, got, file)
+		}
+		if _, err := prompts.Render(process.GetPromptName(), PromptInput{DiffFiles: map[string]string{file: "@@ -1 +1 @@\n+x\n"}}); err != nil {
+			t.Errorf("%s prompt渲染失败：%v", language, err)
+		}
+		if languageDisplayName(language) != spec.DisplayName {
+			t.Errorf("languageDisplayName(%q) = %q，期望%q", language, languageDisplayName(language), spec.DisplayName)
+		}
+	}
+}
+
+// TestLinterSpecRun 测试静态检查工具的退出码处理：发现问题的退出码视为正常，其他非0退出码视为执行失败
+func TestLinterSpecRun(t *testing.T) {
+	linter := LinterSpec{Name: "fake", Command: "sh", Args: []string{"-c", "echo {file}:1: warning; exit 1"}, FindingCodes: []int{1}}
+	if output, err := linter.run("sh", ".", "a.sh"); err != nil || output != "a.sh:1: warning" {
+		t.Errorf("退出码1：output=%q err=%v，期望正常返回输出", output, err)
+	}
+	linter.Args = []string{"-c", "echo {file}:1: warning; exit 2"}
+	if _, err := linter.run("sh", ".", "a.sh"); err == nil || !strings.Contains(err.Error(), "a.sh:1: warning") {
+		t.Errorf("退出码2：err=%v，期望执行失败并附带输出", err)
+	}
+	project := LinterSpec{Args: []string{"clippy"}}
+	if got := project.parse("src/a.rs:1:1: warning: x\nsrc/b.rs:2:1: error: y", "src/b.rs"); got != "src/b.rs:2:1: error: y" {
+		t.Errorf("工程级输出按文件归类结果 = %q", got)
+	}
+}
+
+// TestRegisterLanguages 测试配置文件中声明的语言：覆盖同名内置语言、使用通用prompt模板以及gcc格式输出解析
+func TestRegisterLanguages(t *testing.T) {
+	defer func(registry []*LanguageSpec) { languageRegistry = registry }(languageRegistry)
+
+	registerLanguages([]LanguageSpec{
+		{Name: "lua", DisplayName: "Lua", Extensions: []string{".LUA"}, Dimensions: "全局变量污染、协程使用",
+			Linters: []LinterSpec{{Command: "luacheck", Args: []string{"--formatter=plain", "{file}"}, Parser: LintParserGCC}}},
+		{Name: "python", DisplayName: "Python3", Extensions: []string{".py", ".pyi"}},
+	})
+
+	process := GetReviewProcess("Lua")
+	if process.GetPromptName() != genericPromptName {
+		t.Errorf("lua的prompt模板 = %s，期望%s", process.GetPromptName(), genericPromptName)
+	}
+	if got := process.FilterFiles([]DiffItem{{NewPath: "game/main.lua", Diff: "+"}}); len(got) != 1 {
+		t.Errorf("lua FilterFiles() = %v，期望包含game/main.lua", got)
+	}
+	prompt, err := prompts.Render(process.GetPromptName(), PromptInput{
+		DiffFiles:  map[string]string{"game/main.lua": "@@ -1 +1 @@\n+x = 1\n"},
+		Language:   languageDisplayName("lua"),
+		Dimensions: languageDimensions(process),
+	})
+	if err != nil || !strings.Contains(prompt, "资深Lua工程师") || !strings.Contains(prompt, "全局变量污染、协程使用") {
+		t.Errorf("lua prompt渲染结果不符合预期：%v\n%s", err, prompt)
+	}
+	if got := languageDisplayName("python"); got != "Python3" {
+		t.Errorf("覆盖内置语言后的展示名 = %s，期望Python3", got)
+	}
+	if got := GetReviewProcess("python").GetPromptName(); got != "python" {
+		t.Errorf("覆盖内置语言后的prompt模板 = %s，期望python", got)
+	}
+
+	linter := LinterSpec{Args: []string{"{file}"}, Parser: LintParserGCC}
+	output := "game/main.lua:3:7: (W111) setting non-standard global variable 'x'\ngame/other.lua:1:1: (W211) unused\nTotal: 2 warnings"
+	if got := linter.parse(output, "game/main.lua"); got != "3:7: (W111) setting non-standard global variable 'x'" {
+		t.Errorf("gcc格式解析结果 = %q", got)
+	}
+}
+
+// TestInfrastructureLanguages 测试基础设施即代码文件的识别：无后缀的文件名、YAML中的Kubernetes标记以及检查工具的适用范围
+func TestInfrastructureLanguages(t *testing.T) {
+	items := []DiffItem{
+		{NewPath: "build/Dockerfile.prod", Diff: "+FROM golang:1.23\n"},
+		{NewPath: "deploy/app.yaml", Diff: "+apiVersion: apps/v1\n+kind: Deployment\n"},
+		{NewPath: "charts/app/templates/_helpers.tpl", Diff: "+{{- define \"app.name\" -}}\n"},
+		{NewPath: "docker-compose.yml", Diff: "+services:\n"},
+		{NewPath: "infra/main.tf", Diff: "+resource \"alicloud_oss_bucket\" \"logs\" {}\n"},
+		{NewPath: ".github/workflows/ci.yml", Diff: "+on: push\n"},
+		{NewPath: ".gitlab-ci.yml", Diff: "+stages: [test]\n"},
+	}
+	cases := map[string][]string{
+		"docker":    {"build/Dockerfile.prod"},
+		"k8s":       {"deploy/app.yaml", "charts/app/templates/_helpers.tpl"},
+		"terraform": {"infra/main.tf"},
+		"ci":        {".github/workflows/ci.yml", ".gitlab-ci.yml"},
+	}
+	for language, want := range cases {
+		process := GetReviewProcess(language)
+		got := process.FilterFiles(items)
+		if len(got) != len(want) {
+			t.Errorf("%s FilterFiles() = %v，期望%v", language, got, want)
+			continue
+		}
+		for _, file := range want {
+			if _, ok := got[file]; !ok {
+				t.Errorf("%s FilterFiles()缺少%s", language, file)
+			}
+		}
+		if _, err := prompts.Render(process.GetPromptName(), PromptInput{DiffFiles: got}); err != nil {
+			t.Errorf("%s prompt渲染失败：%v", language, err)
+		}
+	}
+
+	actionlint := lookupLanguage("ci").Linters[0]
+	if !actionlint.applies(".github/workflows/ci.yml") || actionlint.applies(".gitlab-ci.yml") {
+		t.Errorf("actionlint应只检查GitHub Actions工作流")
+	}
+}
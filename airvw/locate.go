@@ -0,0 +1,106 @@
+package main
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// 问题位置的校验结果
+const (
+	LocationSnapped     = "snapped"      // 行号不在变更范围内，已修正到最近的新增行
+	LocationOutsideDiff = "outside_diff" // 行号距离变更较远，保留原行号
+	LocationUnknownFile = "unknown_file" // 文件不在本次变更中
+)
+
+// locateSnapTolerance 行号与最近新增行相差不超过该值时修正到新增行
+const locateSnapTolerance = 5
+
+// locateFindings 校验问题的文件和行号是否位于本次diff中：文件路径按后缀唯一匹配规范化，
+// 不在diff可见范围内的行号修正到附近的新增行，引用了变更之外文件的问题单独返回，不参与卡点和评论
+func locateFindings(issues []BlockIssue, diffFiles map[string]string) ([]BlockIssue, []BlockIssue) {
+	kinds := make(map[string]map[int]byte, len(diffFiles))
+	var kept, unlocated []BlockIssue
+	for _, issue := range issues {
+		if issue.File == "unknown" {
+			kept = append(kept, issue)
+			continue
+		}
+
+		file, ok := resolveDiffFile(issue.File, diffFiles)
+		if !ok {
+			logDebug("📍【locateFindings】%s不在本次变更中，忽略问题：%s\n", issue.File, issue.Issue)
+			issue.Location = LocationUnknownFile
+			unlocated = append(unlocated, issue)
+			continue
+		}
+		if file != issue.File {
+			logDebug("📍【locateFindings】文件路径%s修正为%s\n", issue.File, file)
+			issue.File = file
+		}
+
+		if kinds[file] == nil {
+			kinds[file] = diffLineKinds(diffFiles[file])
+		}
+		locateLine(&issue, kinds[file])
+		kept = append(kept, issue)
+	}
+	return kept, unlocated
+}
+
+// locateLine 校验行号：位于diff可见范围（新增行或上下文行）内时保持不变，否则修正到容差范围内最近的新增行
+func locateLine(issue *BlockIssue, kinds map[int]byte) {
+	if len(kinds) == 0 {
+		return
+	}
+	lineNo, _ := strconv.Atoi(issue.Line)
+	if _, ok := kinds[lineNo]; ok {
+		return
+	}
+
+	nearest := 0
+	for n, kind := range kinds {
+		if kind != '+' {
+			continue
+		}
+		if nearest == 0 || abs(n-lineNo) < abs(nearest-lineNo) || (abs(n-lineNo) == abs(nearest-lineNo) && n < nearest) {
+			nearest = n
+		}
+	}
+	if nearest == 0 || (lineNo > 0 && abs(nearest-lineNo) > locateSnapTolerance) {
+		logDebug("📍【locateFindings】%s:%s不在变更范围内\n", issue.File, issue.Line)
+		issue.Location = LocationOutsideDiff
+		return
+	}
+	logDebug("📍【locateFindings】%s的行号由%s修正为%d\n", issue.File, issue.Line, nearest)
+	issue.OriginalLine = issue.Line
+	issue.Line = strconv.Itoa(nearest)
+	issue.Location = LocationSnapped
+}
+
+// resolveDiffFile 将问题中的文件路径匹配到diff中的文件：先精确匹配，再去掉./、a/、b/前缀，最后按路径后缀唯一匹配
+func resolveDiffFile(file string, diffFiles map[string]string) (string, bool) {
+	if _, ok := diffFiles[file]; ok {
+		return file, true
+	}
+	cleaned := path.Clean(strings.ReplaceAll(file, "\\", "/"))
+	for _, prefix := range []string{"a/", "b/"} {
+		if _, ok := diffFiles[strings.TrimPrefix(cleaned, prefix)]; ok {
+			return strings.TrimPrefix(cleaned, prefix), true
+		}
+	}
+	if _, ok := diffFiles[cleaned]; ok {
+		return cleaned, true
+	}
+
+	var candidates []string
+	for candidate := range diffFiles {
+		if candidate == cleaned || strings.HasSuffix(candidate, "/"+cleaned) || strings.HasSuffix(cleaned, "/"+candidate) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+	return "", false
+}
@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+// TestLocateFindings 测试文件路径规范化、行号修正以及变更之外文件的问题
+func TestLocateFindings(t *testing.T) {
+	diffFiles := map[string]string{
+		"svc/order.go": "@@ -10,3 +10,5 @@ func a() {\n \tx := 1\n+\ty := 2\n+\tz := 3\n \treturn\n }\n@@ -40,2 +42,3 @@\n \tfoo()\n+\tbar()\n }\n",
+		"dao/user.go":  "@@ -1,1 +1,2 @@\n package dao\n+var x int\n",
+	}
+	issues := []BlockIssue{
+		{Level: LevelBlock, File: "svc/order.go", Line: "11"},
+		{Level: LevelBlock, File: "svc/order.go", Line: "10"},
+		{Level: LevelHigh, File: "./svc/order.go", Line: "15"},
+		{Level: LevelHigh, File: "order.go", Line: "47"},
+		{Level: LevelHigh, File: "b/dao/user.go", Line: "100"},
+		{Level: LevelMedium, File: "svc/payment.go", Line: "3"},
+		{Level: LevelHigh, File: "unknown", Line: "0"},
+	}
+
+	want := []struct{ file, line, location, original string }{
+		{"svc/order.go", "11", "", ""},
+		{"svc/order.go", "10", "", ""},
+		{"svc/order.go", "12", LocationSnapped, "15"},
+		{"svc/order.go", "43", LocationSnapped, "47"},
+		{"dao/user.go", "100", LocationOutsideDiff, ""},
+		{"unknown", "0", "", ""},
+	}
+	kept, unlocated := locateFindings(issues, diffFiles)
+	if len(kept) != len(want) {
+		t.Fatalf("保留%d个问题，期望%d个：%+v", len(kept), len(want), kept)
+	}
+	for i, w := range want {
+		got := kept[i]
+		if got.File != w.file || got.Line != w.line || got.Location != w.location || got.OriginalLine != w.original {
+			t.Errorf("第%d个问题 = %s:%s(%s,%s)，期望%s:%s(%s,%s)", i+1,
+				got.File, got.Line, got.Location, got.OriginalLine, w.file, w.line, w.location, w.original)
+		}
+	}
+	if len(unlocated) != 1 || unlocated[0].File != "svc/payment.go" || unlocated[0].Location != LocationUnknownFile {
+		t.Errorf("变更之外的问题 = %+v，期望svc/payment.go", unlocated)
+	}
+}
@@ -5,13 +5,10 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
-	"regexp"
 	"sort"
 	"strings"
 	"time"
 
-	"github.com/blinkbean/dingtalk"
 	"github.com/go-resty/resty/v2"
 )
 
@@ -24,24 +21,43 @@ const (
 
 // Config 综合配置结构体（新增评论目标/CommitID）
 type Config struct {
-	YunxiaoToken   string // 云效Token（x-yunxiao-token）
-	OrgID          string // 组织ID（如67aaaaaaaaaa）
-	RepoID         int    // 仓库ID（如5023797）
-	MRID           int    // MR的ID（changeRequestId，评论MR时必填）
-	FromCommit     string // 源提交ID（commit hash）
-	ToCommit       string // 目标提交ID（commit hash）
-	CodeupDomain   string // 云效域名，默认openapi-rdc.aliyuncs.com
-	BaichuanAPIKey string // 阿里云百炼API Key
-	ReviewLevel    string // 评审等级，默认block
-	CommentTarget  string // 评论目标：mr（默认）/commit/空（不评论）
-	CommitID       string // 评论Commit时的commit hash（comment-target=commit时必填）
-	Language       string // 评审语言：golang/java/python/javascript（默认golang）
-	Model          string // AI模型名称，默认qwen3-coder-plus
-	Debug          bool   // 是否开启调试模式，默认false
-	DingTalkToken  string // 钉钉机器人Token
-	DingTalkSecret string // 钉钉机器人Secret
-	EnableDingTalk bool   // 是否启用钉钉通知，默认false
-	MaxIssues      int    // 钉钉通知中显示的最大问题数量，默认10
+	YunxiaoToken      string  // 云效Token（x-yunxiao-token）
+	OrgID             string  // 组织ID（如67aaaaaaaaaa）
+	RepoID            int     // 仓库ID（如5023797）
+	MRID              int     // MR的ID（changeRequestId，评论MR时必填）
+	FromCommit        string  // 源提交ID（commit hash）
+	ToCommit          string  // 目标提交ID（commit hash）
+	CodeupDomain      string  // 云效域名，默认openapi-rdc.aliyuncs.com
+	BaichuanAPIKey    string  // 阿里云百炼API Key
+	ReviewLevel       string  // 评审等级，默认block
+	CommentTarget     string  // 评论目标：mr（默认）/commit/空（不评论）
+	CommitID          string  // 评论Commit时的commit hash（comment-target=commit时必填）
+	Language          string  // 评审语言：内置语言或配置文件中声明的语言（默认golang）
+	Model             string  // AI模型名称，默认qwen3-coder-plus
+	Debug             bool    // 是否开启调试模式，默认false
+	DingTalkToken     string  // 钉钉机器人Token
+	DingTalkSecret    string  // 钉钉机器人Secret
+	EnableDingTalk    bool    // 是否启用钉钉通知，默认false
+	MaxIssues         int     // 钉钉通知中显示的最大问题数量，默认10
+	TargetBranch      string  // MR目标分支（未指定时从MR详情获取），用于判断是否受保护分支
+	ConfigFile        string  // 配置文件路径，默认.airvw.json
+	Notify            string  // 启用的通知渠道名称（逗号分隔），空则启用全部已配置渠道
+	Locale            string  // 评论/通知语言区域：zh/en，默认zh
+	NotifyRetries     int     // 通知发送失败后的重试次数，默认2
+	FailOnNotifyError bool    // 是否将所有通知视为必需（失败时以非0退出），默认false
+	ReportFile        string  // 评审结果JSON报告文件路径（可选）
+	PromptDir         string  // prompt模板覆盖目录（可选）
+	MinConfidence     float64 // block/high级问题参与卡点的最低置信度（可选，0表示不限制）
+	Advisory          bool    // advisory模式：违反卡点策略时只报告不终止流程
+	Samples           int     // 评审采样次数，大于1时按多数投票保留问题，默认1
+	Verify            bool    // 是否对block/high级问题进行复核
+	VerifyModel       string  // 复核使用的模型（可选，默认与评审模型相同）
+	Baseline          string  // 基线文件路径（可选，默认读取当前目录下的.airvw-baseline.json）
+	AuditLog          string  // 模型调用审计日志路径（可选）
+	Profile           string  // 评审模式：default/security，默认default
+	SARIFFile         string  // SARIF格式结果输出路径（可选）
+	CoverageFile      string  // 覆盖率文件路径（可选），指定后开启测试缺口检查
+	File              FileConfig
 }
 
 // DiffItem 对应接口返回的diffs数组元素
@@ -116,56 +132,75 @@ func logDebugln(args ...interface{}) {
 
 // BlockIssue 阻断问题结构体
 type BlockIssue struct {
-	Level      string `json:"level"`      // 问题等级
-	File       string `json:"file"`       // 文件名
-	Line       string `json:"line"`       // 行号
-	Issue      string `json:"issue"`      // 问题描述
-	Suggestion string `json:"suggestion"` // 修复建议
+	Level        string  `json:"level"`                   // 问题等级
+	File         string  `json:"file"`                    // 文件名
+	Line         string  `json:"line"`                    // 行号
+	Issue        string  `json:"issue"`                   // 问题描述
+	Suggestion   string  `json:"suggestion"`              // 修复建议
+	RuleID       string  `json:"rule_id,omitempty"`       // 命中的团队自定义规则ID
+	Known        bool    `json:"known,omitempty"`         // 是否为基线中的存量问题（不参与卡点）
+	PromotedFrom string  `json:"promoted_from,omitempty"` // 按卡点策略提升等级前的原等级
+	Confidence   float64 `json:"confidence,omitempty"`    // 模型给出的置信度（0~1），0表示未给出
+	Advisory     bool    `json:"advisory,omitempty"`      // 置信度低于阈值，仅提示不参与卡点
+	Votes        string  `json:"votes,omitempty"`         // 多次采样时获得的票数，如2/3
+	Verification string  `json:"verification,omitempty"`  // 复核结论：confirmed/downgraded/rejected
+	VerifyReason string  `json:"verify_reason,omitempty"` // 复核理由
+	Location     string  `json:"location,omitempty"`      // 位置校验结果：snapped/outside_diff/unknown_file，为空表示位于变更范围内
+	OriginalLine string  `json:"original_line,omitempty"` // 行号被修正前模型给出的行号
+	Secret       bool    `json:"secret,omitempty"`        // 是否为内置密钥扫描发现的凭证泄漏（始终参与卡点）
+	Scanner      string  `json:"scanner,omitempty"`       // 发现问题的内置扫描：secret/dependency/test-gap，为空表示模型评审发现的问题
+	CWE          string  `json:"cwe,omitempty"`           // CWE编号，如CWE-89
+	OWASP        string  `json:"owasp,omitempty"`         // OWASP Top 10分类，如A03:2021
 }
 
+// 内置扫描，扫描结果是确定性的，不经过模型复核
+const (
+	ScannerSecret     = "secret"     // 密钥泄漏扫描
+	ScannerDependency = "dependency" // 依赖漏洞与许可证检查
+	ScannerTestGap    = "test-gap"   // 测试缺口检查
+)
+
 // ReviewResult 评审结果结构体
 type ReviewResult struct {
-	Status      string       `json:"status"`                 // 状态: success/blocked
-	TotalIssues int          `json:"total_issues"`           // 总问题数
-	BlockReason string       `json:"block_reason,omitempty"` // 阻断原因
-	BlockIssues []BlockIssue `json:"block_issues,omitempty"` // 阻断问题列表
-	Message     string       `json:"message"`                // 消息
-	CommitInfo  *CommitInfo  `json:"commit_info,omitempty"`  // Commit信息
-	Model       string       `json:"model,omitempty"`        // 使用的AI模型
+	Status        string            `json:"status"`                   // 状态: success/blocked
+	TotalIssues   int               `json:"total_issues"`             // 总问题数
+	BlockReason   string            `json:"block_reason,omitempty"`   // 阻断原因
+	BlockIssues   []BlockIssue      `json:"block_issues,omitempty"`   // 阻断问题列表
+	Message       string            `json:"message"`                  // 消息
+	CommitInfo    *CommitInfo       `json:"commit_info,omitempty"`    // Commit信息
+	MRInfo        *MRInfo           `json:"mr_info,omitempty"`        // MR信息
+	Model         string            `json:"model,omitempty"`          // 使用的AI模型
+	PromptVersion string            `json:"prompt_version,omitempty"` // 使用的prompt模板版本
+	KnownIssues   int               `json:"known_issues,omitempty"`   // 命中基线的存量问题数
+	Policy        *PolicyDecision   `json:"policy,omitempty"`         // 卡点策略判定结果
+	Rejected      []BlockIssue      `json:"rejected,omitempty"`       // 复核驳回的问题
+	Unlocated     []BlockIssue      `json:"unlocated,omitempty"`      // 引用了本次变更之外文件的问题
+	Suppressed    []SuppressedIssue `json:"suppressed,omitempty"`     // 被源码中airvw:ignore注释忽略的问题
+	SideEffects   []SideEffect      `json:"side_effects,omitempty"`   // 评论/通知/报告的执行结果
 }
 
 // CommitInfo Commit信息结构体
 type CommitInfo struct {
-	AuthorName string `json:"author_name"` // 提交人姓名
-	Message    string `json:"message"`     // 提交消息
+	AuthorName  string `json:"author_name"`            // 提交人姓名
+	AuthorEmail string `json:"author_email,omitempty"` // 提交人邮箱
+	Message     string `json:"message"`                // 提交消息
 }
 
-// formatBlockIssues 将问题字符串转换为结构化的BlockIssue，并按重要性等级排序
-func formatBlockIssues(issues []string) []BlockIssue {
-	var blockIssues []BlockIssue
-	for _, issue := range issues {
-		// 解析格式: [等级] 文件名:行号 - 问题描述 - 修复建议
-		re := regexp.MustCompile(`\[([^\]]+)\]\s*([^:]+):\s*(\d+)\s*-\s*([^\-]+)\s*-\s*(.+)`)
-		matches := re.FindStringSubmatch(issue)
-		if len(matches) == 6 {
-			blockIssues = append(blockIssues, BlockIssue{
-				Level:      matches[1],
-				File:       matches[2],
-				Line:       matches[3],
-				Issue:      strings.TrimSpace(matches[4]),
-				Suggestion: strings.TrimSpace(matches[5]),
-			})
-		} else {
-			// 如果无法解析，则将整个字符串作为问题描述
-			blockIssues = append(blockIssues, BlockIssue{
-				Level:      "unknown",
-				File:       "unknown",
-				Line:       "0",
-				Issue:      issue,
-				Suggestion: "",
-			})
-		}
-	}
+// MRInfo MR信息结构体
+type MRInfo struct {
+	Author       *MRUser  `json:"author,omitempty"`        // MR作者
+	Reviewers    []MRUser `json:"reviewers,omitempty"`     // 评审人
+	TargetBranch string   `json:"target_branch,omitempty"` // 目标分支
+}
+
+// MRUser MR参与人
+type MRUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+// sortBlockIssues 按重要性等级排序问题，同等级按置信度从高到低（稳定排序）
+func sortBlockIssues(issues []BlockIssue) {
 	// 按重要性等级排序：block > high > medium > suggest > unknown
 	levelPriority := map[string]int{
 		LevelBlock:   0,
@@ -175,19 +210,21 @@ func formatBlockIssues(issues []string) []BlockIssue {
 		"unknown":    4,
 	}
 
-	sort.Slice(blockIssues, func(i, j int) bool {
-		priorityI, okI := levelPriority[blockIssues[i].Level]
-		priorityJ, okJ := levelPriority[blockIssues[j].Level]
+	sort.SliceStable(issues, func(i, j int) bool {
+		priorityI, okI := levelPriority[issues[i].Level]
+		priorityJ, okJ := levelPriority[issues[j].Level]
 		if !okI {
 			priorityI = 4
 		}
 		if !okJ {
 			priorityJ = 4
 		}
-		return priorityI < priorityJ
+		if priorityI != priorityJ {
+			return priorityI < priorityJ
+		}
+		// 同等级按置信度从高到低，未给出置信度的排在最后
+		return issues[i].Confidence > issues[j].Confidence
 	})
-
-	return blockIssues
 }
 
 // printJSONResult 以JSON格式输出评审结果
@@ -200,738 +237,18 @@ func printJSONResult(result ReviewResult) {
 	fmt.Println(string(jsonData))
 }
 
-// DingDingRemind 发送钉钉消息通知
-func DingDingRemind(token, secret, content string, maxIssues int) {
-	// 初始化钉钉客户端（自动处理加签逻辑）
-	cli := dingtalk.InitDingTalkWithSecret(token, secret)
-
-	// 解析ReviewResult
-	var result ReviewResult
-	if err := json.Unmarshal([]byte(content), &result); err != nil {
-		logDebug("解析评审结果失败: %v \n", err)
-		return
-	}
-
-	// 构建Markdown消息
-	var markdown strings.Builder
-	markdown.WriteString("## AI代码审查结果通知\n\n")
-
-	// 添加状态
-	if result.Status == "blocked" {
-		markdown.WriteString("### ❌ 评审被阻断\n\n")
-	} else {
-		markdown.WriteString("### ✅ 评审通过\n\n")
-	}
-
-	// 添加commit信息
-	if result.CommitInfo != nil {
-		markdown.WriteString("### 📝 Commit信息\n\n")
-		markdown.WriteString(fmt.Sprintf("- **提交人**: %s\n", result.CommitInfo.AuthorName))
-		markdown.WriteString(fmt.Sprintf("- **提交消息**: %s\n", result.CommitInfo.Message))
-		//markdown.WriteString(fmt.Sprintf("- **Web链接**: [%s](%s)\n\n", result.CommitInfo.WebUrl, result.CommitInfo.WebUrl))
-	}
-
-	// 添加评审结果
-	markdown.WriteString("### 📊 评审结果\n\n")
-	markdown.WriteString(fmt.Sprintf("- **状态**: %s\n", result.Status))
-	markdown.WriteString(fmt.Sprintf("- **问题数量**: %d\n", result.TotalIssues))
-	if result.BlockReason != "" {
-		markdown.WriteString(fmt.Sprintf("- **阻断原因**: %s\n", result.BlockReason))
-	}
-	markdown.WriteString(fmt.Sprintf("- **消息**: %s\n\n", result.Message))
-
-	// 添加AI模型信息
-	if result.Model != "" {
-		markdown.WriteString("### 🤖 AI模型\n\n")
-		markdown.WriteString(fmt.Sprintf("- **模型**: %s\n\n", result.Model))
-	}
-
-	// 添加问题详情
-	if len(result.BlockIssues) > 0 {
-		markdown.WriteString("### 🐛 问题和建议\n\n")
-		// 限制显示的问题数量
-		issuesToShow := result.BlockIssues
-		if maxIssues > 0 && len(issuesToShow) > maxIssues {
-			issuesToShow = issuesToShow[:maxIssues]
-			markdown.WriteString(fmt.Sprintf("**注意**: 仅显示前%d个问题（共%d个）\n\n", maxIssues, result.TotalIssues))
-		}
-		for i, issue := range issuesToShow {
-			markdown.WriteString(fmt.Sprintf("**%d. [%s] %s:%s**\n\n", i+1, issue.Level, issue.File, issue.Line))
-			markdown.WriteString(fmt.Sprintf("- 问题描述: %s\n", issue.Issue))
-			if issue.Suggestion != "" {
-				markdown.WriteString(fmt.Sprintf("- 修复建议: %s\n", issue.Suggestion))
-			}
-			markdown.WriteString("\n")
-		}
-	}
-
-	// 发送Markdown消息，支持@所有人（也可自定义@指定人）
-	// 第一个参数是Markdown消息的标题，第二个是内容，第三个是可选配置（如@所有人）
-	err := cli.SendMarkDownMessage("AI代码审查结果通知", markdown.String(), dingtalk.WithAtAll())
-	//err := cli.SendMarkdownMessage("AI代码审查结果通知", markdown.String(), dingtalk.WithAtAll())
-	if err != nil {
-		logDebugln("钉钉机器人发送失败: %v\n", err)
-		return
-	}
-	logDebugln("钉钉消息发送成功！")
-}
-
 // ReviewProcess 代码评审流程接口
 type ReviewProcess interface {
 	// GetFileExtension 获取需要评审的文件扩展名
 	GetFileExtension() string
-	// GetPrompt 获取AI评审的prompt
-	GetPrompt(diffFiles map[string]string, lintResults map[string]string) string
+	// GetPromptName 获取AI评审prompt模板名称（对应prompts目录下的<名称>.tmpl）
+	GetPromptName() string
 	// RunLint 执行代码静态检查
 	RunLint(repoPath string, diffFiles map[string]string) map[string]string
 	// FilterFiles 过滤需要评审的文件
 	FilterFiles(diffItems []DiffItem) map[string]string
 }
 
-// GolangReviewProcess Golang语言的评审流程实现
-type GolangReviewProcess struct{}
-
-func (g *GolangReviewProcess) GetFileExtension() string {
-	return ".go"
-}
-
-func (g *GolangReviewProcess) GetPrompt(diffFiles map[string]string, lintResults map[string]string) string {
-	var reviewContent string
-	for file, content := range diffFiles {
-		reviewContent += fmt.Sprintf("=== 文件：%s ===\n规则检查结果：%s\n代码变更内容：\n%s\n\n",
-			file, lintResults[file], content)
-	}
-
-	return fmt.Sprintf(`
-你是资深Golang工程师，仅评审Codeup MR中新增/修改的Go代码，严格按以下要求输出：
-1. 评审维度：并发安全、Error处理、内存优化、代码规范、逻辑漏洞、性能问题、内存泄漏、竞态检查、空指针解引用、内存溢出；
-2. 每个问题必须标注等级，等级仅能是[%s/%s/%s/%s]，其中[%s]级问题直接阻断MR合并；
-3. 输出格式：每行一个问题，格式为「[等级] 文件名:行号 - 问题描述 - 修复建议」；
-4. 仅输出问题列表，无冗余前言/结语，无代码块，每行一条；
-5. 若无问题，仅输出「✅ 未发现任何问题」。
-
-待评审的MR变更代码-
----------------------
-%s`, LevelBlock, LevelHigh, LevelMedium, LevelSuggest, LevelBlock, reviewContent)
-}
-
-func (g *GolangReviewProcess) RunLint(repoPath string, diffFiles map[string]string) map[string]string {
-	logDebugln("\n=====================================")
-	logDebugln("【RunGolangciLint】开始执行")
-	logDebug("  - 仓库路径：%s\n", repoPath)
-	logDebug("  - 待检查文件数：%d\n", len(diffFiles))
-	logDebugln("=====================================")
-
-	lintResults := make(map[string]string)
-
-	if _, err := exec.LookPath("golangci-lint"); err != nil {
-		logDebugln("⚠️【RunGolangciLint】未检测到golangci-lint，跳过规则检查")
-		for file := range diffFiles {
-			lintResults[file] = "【规则检查】未执行：缺少golangci-lint环境"
-		}
-		return lintResults
-	}
-
-	for file := range diffFiles {
-		logDebug("ℹ️【RunGolangciLint】检查文件：%s\n", file)
-		cmd := exec.Command("bash", "-c",
-			fmt.Sprintf("cd %s && golangci-lint run --new-from-rev=origin/main %s", repoPath, file))
-		output, err := cmd.CombinedOutput()
-
-		if err != nil {
-			logDebug("⚠️【RunGolangciLint】文件%s检查失败：%v\n", file, err)
-			lintResults[file] = fmt.Sprintf("【规则检查】执行失败：%s，输出：%s", err.Error(), string(output))
-			continue
-		}
-
-		if string(output) == "" {
-			logDebug("✅【RunGolangciLint】文件%s未发现违规问题\n", file)
-			lintResults[file] = "【规则检查】未发现违规问题"
-		} else {
-			logDebug("⚠️【RunGolangciLint】文件%s发现违规问题：%s\n", file, string(output))
-			lintResults[file] = fmt.Sprintf("【规则检查】发现问题：%s", string(output))
-		}
-	}
-
-	return lintResults
-}
-
-func (g *GolangReviewProcess) FilterFiles(diffItems []DiffItem) map[string]string {
-	diffMap := make(map[string]string)
-	for _, diffItem := range diffItems {
-		// 跳过二进制文件
-		if diffItem.Binary {
-			logDebug("ℹ️【GetMRDiff】跳过二进制文件：%s\n", diffItem.NewPath)
-			continue
-		}
-
-		// 确定文件路径（兼容重命名/删除场景）
-		filePath := diffItem.NewPath
-		if filePath == "" {
-			filePath = diffItem.OldPath
-		}
-
-		// 确定文件状态
-		var status string
-		if diffItem.NewFile {
-			status = "added"
-		} else if diffItem.DeletedFile {
-			status = "removed"
-		} else if diffItem.RenamedFile {
-			status = "renamed"
-		} else {
-			status = "modified"
-		}
-
-		// 仅保留新增/修改的Go文件
-		if (status == "added" || status == "modified") && strings.HasSuffix(filePath, ".go") {
-			diffMap[filePath] = diffItem.Diff
-			logDebug("✅【GetMRDiff】检测到需评审文件：%s（状态：%s）\n", filePath, status)
-		}
-	}
-	return diffMap
-}
-
-// JavaReviewProcess Java语言的评审流程实现
-type JavaReviewProcess struct{}
-
-func (j *JavaReviewProcess) GetFileExtension() string {
-	return ".java"
-}
-
-func (j *JavaReviewProcess) GetPrompt(diffFiles map[string]string, lintResults map[string]string) string {
-	var reviewContent string
-	for file, content := range diffFiles {
-		reviewContent += fmt.Sprintf("=== 文件：%s ===\n规则检查结果：%s\n代码变更内容：\n%s\n\n",
-			file, lintResults[file], content)
-	}
-
-	return fmt.Sprintf(`
-你是资深Java工程师，仅评审Codeup MR中新增/修改的Java代码，严格按以下要求输出：
-1. 评审维度：并发安全、异常处理、内存优化、代码规范、逻辑漏洞、性能问题、资源泄漏、空指针异常、集合使用、线程安全；
-2. 每个问题必须标注等级，等级仅能是[%s/%s/%s/%s]，其中[%s]级问题直接阻断MR合并；
-3. 输出格式：每行一个问题，格式为「[等级] 文件名:行号 - 问题描述 - 修复建议」；
-4. 仅输出问题列表，无冗余前言/结语，无代码块，每行一条；
-5. 若无问题，仅输出「✅ 未发现任何问题」。
-
-待评审的MR变更代码-
----------------------
-%s`, LevelBlock, LevelHigh, LevelMedium, LevelSuggest, LevelBlock, reviewContent)
-}
-
-func (j *JavaReviewProcess) RunLint(repoPath string, diffFiles map[string]string) map[string]string {
-	fmt.Println("\n=====================================")
-	fmt.Println("【RunJavaLint】开始执行")
-	fmt.Printf("  - 仓库路径：%s\n", repoPath)
-	fmt.Printf("  - 待检查文件数：%d\n", len(diffFiles))
-	fmt.Println("=====================================")
-
-	lintResults := make(map[string]string)
-
-	// 检查是否安装了Checkstyle
-	if _, err := exec.LookPath("checkstyle"); err != nil {
-		fmt.Println("⚠️【RunJavaLint】未检测到checkstyle，跳过规则检查")
-		for file := range diffFiles {
-			lintResults[file] = "【规则检查】未执行：缺少checkstyle环境"
-		}
-		return lintResults
-	}
-
-	for file := range diffFiles {
-		fmt.Printf("ℹ️【RunJavaLint】检查文件：%s\n", file)
-		cmd := exec.Command("checkstyle", "-c", "/google_checks.xml", file)
-		output, err := cmd.CombinedOutput()
-
-		if err != nil {
-			fmt.Printf("⚠️【RunJavaLint】文件%s检查失败：%v\n", file, err)
-			lintResults[file] = fmt.Sprintf("【规则检查】执行失败：%s，输出：%s", err.Error(), string(output))
-			continue
-		}
-
-		if string(output) == "" {
-			fmt.Printf("✅【RunJavaLint】文件%s未发现违规问题\n", file)
-			lintResults[file] = "【规则检查】未发现违规问题"
-		} else {
-			fmt.Printf("⚠️【RunJavaLint】文件%s发现违规问题：%s\n", file, string(output))
-			lintResults[file] = fmt.Sprintf("【规则检查】发现问题：%s", string(output))
-		}
-	}
-
-	return lintResults
-}
-
-func (j *JavaReviewProcess) FilterFiles(diffItems []DiffItem) map[string]string {
-	diffMap := make(map[string]string)
-	for _, diffItem := range diffItems {
-		// 跳过二进制文件
-		if diffItem.Binary {
-			logDebug("ℹ️【GetMRDiff】跳过二进制文件：%s\n", diffItem.NewPath)
-			continue
-		}
-
-		// 确定文件路径（兼容重命名/删除场景）
-		filePath := diffItem.NewPath
-		if filePath == "" {
-			filePath = diffItem.OldPath
-		}
-
-		// 确定文件状态
-		var status string
-		if diffItem.NewFile {
-			status = "added"
-		} else if diffItem.DeletedFile {
-			status = "removed"
-		} else if diffItem.RenamedFile {
-			status = "renamed"
-		} else {
-			status = "modified"
-		}
-
-		// 仅保留新增/修改的Java文件
-		if (status == "added" || status == "modified") && strings.HasSuffix(filePath, ".java") {
-			diffMap[filePath] = diffItem.Diff
-			logDebug("✅【GetMRDiff】检测到需评审文件：%s（状态：%s）\n", filePath, status)
-		}
-	}
-	return diffMap
-}
-
-// PythonReviewProcess Python语言的评审流程实现
-type PythonReviewProcess struct{}
-
-func (p *PythonReviewProcess) GetFileExtension() string {
-	return ".py"
-}
-
-// JavaScriptReviewProcess JavaScript语言的评审流程实现
-type JavaScriptReviewProcess struct{}
-
-func (j *JavaScriptReviewProcess) GetFileExtension() string {
-	return ".js/.jsx/.ts/.tsx"
-}
-
-// KotlinReviewProcess Kotlin语言的评审流程实现
-type KotlinReviewProcess struct{}
-
-func (k *KotlinReviewProcess) GetFileExtension() string {
-	return ".kt"
-}
-
-// SwiftReviewProcess Swift语言的评审流程实现
-type SwiftReviewProcess struct{}
-
-func (s *SwiftReviewProcess) GetFileExtension() string {
-	return ".swift"
-}
-
-func (p *PythonReviewProcess) GetPrompt(diffFiles map[string]string, lintResults map[string]string) string {
-	var reviewContent string
-	for file, content := range diffFiles {
-		reviewContent += fmt.Sprintf("=== 文件：%s ===\n规则检查结果：%s\n代码变更内容：\n%s\n\n",
-			file, lintResults[file], content)
-	}
-
-	return fmt.Sprintf(`
-你是资深Python工程师，仅评审Codeup MR中新增/修改的Python代码，严格按以下要求输出：
-1. 评审维度：异常处理、代码规范(PEP8)、逻辑漏洞、性能问题、资源泄漏、类型注解、导入管理、文档字符串；
-2. 每个问题必须标注等级，等级仅能是[%s/%s/%s/%s]，其中[%s]级问题直接阻断MR合并；
-3. 输出格式：每行一个问题，格式为「[等级] 文件名:行号 - 问题描述 - 修复建议」；
-4. 仅输出问题列表，无冗余前言/结语，无代码块，每行一条；
-5. 若无问题，仅输出「✅ 未发现任何问题」。
-
-待评审的MR变更代码-
----------------------
-%s`, LevelBlock, LevelHigh, LevelMedium, LevelSuggest, LevelBlock, reviewContent)
-}
-
-func (s *SwiftReviewProcess) GetPrompt(diffFiles map[string]string, lintResults map[string]string) string {
-	var reviewContent string
-	for file, content := range diffFiles {
-		reviewContent += fmt.Sprintf("=== 文件：%s ===\n规则检查结果：%s\n代码变更内容：\n%s\n\n",
-			file, lintResults[file], content)
-	}
-
-	return fmt.Sprintf(`
-你是资深Swift工程师，仅评审Codeup MR中新增/修改的Swift代码，严格按以下要求输出：
-1. 评审维度：内存管理、可选项处理、并发安全、错误处理、代码规范、逻辑漏洞、性能问题、资源泄漏、类型安全、协议使用；
-2. 每个问题必须标注等级，等级仅能是[%s/%s/%s/%s]，其中[%s]级问题直接阻断MR合并；
-3. 输出格式：每行一个问题，格式为「[等级] 文件名:行号 - 问题描述 - 修复建议」；
-4. 仅输出问题列表，无冗余前言/结语，无代码块，每行一条；
-5. 若无问题，仅输出「✅ 未发现任何问题」。
-
-待评审的MR变更代码-
----------------------
-%s`, LevelBlock, LevelHigh, LevelMedium, LevelSuggest, LevelBlock, reviewContent)
-}
-
-func (j *JavaScriptReviewProcess) GetPrompt(diffFiles map[string]string, lintResults map[string]string) string {
-	var reviewContent string
-	for file, content := range diffFiles {
-		reviewContent += fmt.Sprintf("=== 文件：%s ===\n规则检查结果：%s\n代码变更内容：\n%s\n\n",
-			file, lintResults[file], content)
-	}
-
-	return fmt.Sprintf(`
-你是资深JavaScript/TypeScript工程师，仅评审Codeup MR中新增/修改的JavaScript/TypeScript代码，严格按以下要求输出：
-1. 评审维度：异步编程、错误处理、代码规范(ESLint)、逻辑漏洞、性能问题、内存泄漏、DOM操作、事件处理、跨浏览器兼容性、TypeScript类型安全、JavaScript类型安全、React组件规范；
-2. 每个问题必须标注等级，等级仅能是[%s/%s/%s/%s]，其中[%s]级问题直接阻断MR合并；
-3. 输出格式：每行一个问题，格式为「[等级] 文件名:行号 - 问题描述 - 修复建议」；
-4. 仅输出问题列表，无冗余前言/结语，无代码块，每行一条；
-5. 若无问题，仅输出「✅ 未发现任何问题」。
-
-待评审的MR变更代码-
----------------------
-%s`, LevelBlock, LevelHigh, LevelMedium, LevelSuggest, LevelBlock, reviewContent)
-}
-
-func (k *KotlinReviewProcess) GetPrompt(diffFiles map[string]string, lintResults map[string]string) string {
-	var reviewContent string
-	for file, content := range diffFiles {
-		reviewContent += fmt.Sprintf("=== 文件：%s ===\n规则检查结果：%s\n代码变更内容：\n%s\n\n",
-			file, lintResults[file], content)
-	}
-
-	return fmt.Sprintf(`
-你是资深Kotlin工程师，仅评审Codeup MR中新增/修改的Kotlin代码，严格按以下要求输出：
-1. 评审维度：空安全、协程使用、异常处理、内存优化、代码规范、逻辑漏洞、性能问题、资源泄漏、泛型使用、扩展函数；
-2. 每个问题必须标注等级，等级仅能是[%s/%s/%s/%s]，其中[%s]级问题直接阻断MR合并；
-3. 输出格式：每行一个问题，格式为「[等级] 文件名:行号 - 问题描述 - 修复建议」；
-4. 仅输出问题列表，无冗余前言/结语，无代码块，每行一条；
-5. 若无问题，仅输出「✅ 未发现任何问题」。
-
-待评审的MR变更代码-
----------------------
-%s`, LevelBlock, LevelHigh, LevelMedium, LevelSuggest, LevelBlock, reviewContent)
-}
-
-func (p *PythonReviewProcess) RunLint(repoPath string, diffFiles map[string]string) map[string]string {
-	logDebugln("\n=====================================")
-	logDebugln("【RunPythonLint】开始执行")
-	logDebug("  - 仓库路径：%s\n", repoPath)
-	logDebug("  - 待检查文件数：%d\n", len(diffFiles))
-	logDebugln("=====================================")
-
-	lintResults := make(map[string]string)
-
-	// 检查是否安装了flake8
-	if _, err := exec.LookPath("flake8"); err != nil {
-		logDebugln("⚠️【RunPythonLint】未检测到flake8，跳过规则检查")
-		for file := range diffFiles {
-			lintResults[file] = "【规则检查】未执行：缺少flake8环境"
-		}
-		return lintResults
-	}
-
-	for file := range diffFiles {
-		logDebug("ℹ️【RunPythonLint】检查文件：%s\n", file)
-		cmd := exec.Command("flake8", file)
-		output, err := cmd.CombinedOutput()
-
-		if err != nil {
-			logDebug("⚠️【RunPythonLint】文件%s检查失败：%v\n", file, err)
-			lintResults[file] = fmt.Sprintf("【规则检查】执行失败：%s，输出：%s", err.Error(), string(output))
-			continue
-		}
-
-		if string(output) == "" {
-			logDebug("✅【RunPythonLint】文件%s未发现违规问题\n", file)
-			lintResults[file] = "【规则检查】未发现违规问题"
-		} else {
-			logDebug("⚠️【RunPythonLint】文件%s发现违规问题：%s\n", file, string(output))
-			lintResults[file] = fmt.Sprintf("【规则检查】发现问题：%s", string(output))
-		}
-	}
-
-	return lintResults
-}
-
-func (s *SwiftReviewProcess) RunLint(repoPath string, diffFiles map[string]string) map[string]string {
-	logDebugln("\n=====================================")
-	logDebugln("【RunSwiftLint】开始执行")
-	logDebug("  - 仓库路径：%s\n", repoPath)
-	logDebug("  - 待检查文件数：%d\n", len(diffFiles))
-	logDebugln("=====================================")
-
-	lintResults := make(map[string]string)
-
-	// 检查是否安装了swiftlint
-	if _, err := exec.LookPath("swiftlint"); err != nil {
-		logDebugln("⚠️【RunSwiftLint】未检测到swiftlint，跳过规则检查")
-		for file := range diffFiles {
-			lintResults[file] = "【规则检查】未执行：缺少swiftlint环境"
-		}
-		return lintResults
-	}
-
-	for file := range diffFiles {
-		logDebug("ℹ️【RunSwiftLint】检查文件：%s\n", file)
-		cmd := exec.Command("swiftlint", "lint", file)
-		output, err := cmd.CombinedOutput()
-
-		if err != nil {
-			logDebug("⚠️【RunSwiftLint】文件%s检查失败：%v\n", file, err)
-			lintResults[file] = fmt.Sprintf("【规则检查】执行失败：%s，输出：%s", err.Error(), string(output))
-			continue
-		}
-
-		if string(output) == "" {
-			logDebug("✅【RunSwiftLint】文件%s未发现违规问题\n", file)
-			lintResults[file] = "【规则检查】未发现违规问题"
-		} else {
-			logDebug("⚠️【RunSwiftLint】文件%s发现违规问题：%s\n", file, string(output))
-			lintResults[file] = fmt.Sprintf("【规则检查】发现问题：%s", string(output))
-		}
-	}
-
-	return lintResults
-}
-
-func (p *PythonReviewProcess) FilterFiles(diffItems []DiffItem) map[string]string {
-	diffMap := make(map[string]string)
-	for _, diffItem := range diffItems {
-		// 跳过二进制文件
-		if diffItem.Binary {
-			logDebug("ℹ️【GetMRDiff】跳过二进制文件：%s\n", diffItem.NewPath)
-			continue
-		}
-
-		// 确定文件路径（兼容重命名/删除场景）
-		filePath := diffItem.NewPath
-		if filePath == "" {
-			filePath = diffItem.OldPath
-		}
-
-		// 确定文件状态
-		var status string
-		if diffItem.NewFile {
-			status = "added"
-		} else if diffItem.DeletedFile {
-			status = "removed"
-		} else if diffItem.RenamedFile {
-			status = "renamed"
-		} else {
-			status = "modified"
-		}
-
-		// 仅保留新增/修改的Python文件
-		if (status == "added" || status == "modified") && strings.HasSuffix(filePath, ".py") {
-			diffMap[filePath] = diffItem.Diff
-			logDebug("✅【GetMRDiff】检测到需评审文件：%s（状态：%s）\n", filePath, status)
-		}
-	}
-	return diffMap
-}
-
-func (s *SwiftReviewProcess) FilterFiles(diffItems []DiffItem) map[string]string {
-	diffMap := make(map[string]string)
-	for _, diffItem := range diffItems {
-		// 跳过二进制文件
-		if diffItem.Binary {
-			logDebug("ℹ️【GetMRDiff】跳过二进制文件：%s\n", diffItem.NewPath)
-			continue
-		}
-
-		// 确定文件路径（兼容重命名/删除场景）
-		filePath := diffItem.NewPath
-		if filePath == "" {
-			filePath = diffItem.OldPath
-		}
-
-		// 确定文件状态
-		var status string
-		if diffItem.NewFile {
-			status = "added"
-		} else if diffItem.DeletedFile {
-			status = "removed"
-		} else if diffItem.RenamedFile {
-			status = "renamed"
-		} else {
-			status = "modified"
-		}
-
-		// 仅保留新增/修改的Swift文件
-		if (status == "added" || status == "modified") && strings.HasSuffix(filePath, ".swift") {
-			diffMap[filePath] = diffItem.Diff
-			logDebug("✅【GetMRDiff】检测到需评审文件：%s（状态：%s）\n", filePath, status)
-		}
-	}
-	return diffMap
-}
-
-func (j *JavaScriptReviewProcess) RunLint(repoPath string, diffFiles map[string]string) map[string]string {
-	fmt.Println("\n=====================================")
-	fmt.Println("【RunJavaScriptLint】开始执行")
-	fmt.Printf("  - 仓库路径：%s\n", repoPath)
-	fmt.Printf("  - 待检查文件数：%d\n", len(diffFiles))
-	fmt.Println("=====================================")
-
-	lintResults := make(map[string]string)
-
-	// 检查是否安装了ESLint
-	if _, err := exec.LookPath("eslint"); err != nil {
-		fmt.Println("⚠️【RunJavaScriptLint】未检测到eslint，跳过规则检查")
-		for file := range diffFiles {
-			lintResults[file] = "【规则检查】未执行：缺少eslint环境"
-		}
-		return lintResults
-	}
-
-	for file := range diffFiles {
-		fmt.Printf("ℹ️【RunJavaScriptLint】检查文件：%s\n", file)
-		cmd := exec.Command("eslint", file)
-		output, err := cmd.CombinedOutput()
-
-		if err != nil {
-			fmt.Printf("⚠️【RunJavaScriptLint】文件%s检查失败：%v\n", file, err)
-			lintResults[file] = fmt.Sprintf("【规则检查】执行失败：%s，输出：%s", err.Error(), string(output))
-			continue
-		}
-
-		if string(output) == "" {
-			fmt.Printf("✅【RunJavaScriptLint】文件%s未发现违规问题\n", file)
-			lintResults[file] = "【规则检查】未发现违规问题"
-		} else {
-			fmt.Printf("⚠️【RunJavaScriptLint】文件%s发现违规问题：%s\n", file, string(output))
-			lintResults[file] = fmt.Sprintf("【规则检查】发现问题：%s", string(output))
-		}
-	}
-
-	return lintResults
-}
-
-func (k *KotlinReviewProcess) RunLint(repoPath string, diffFiles map[string]string) map[string]string {
-	logDebugln("\n=====================================")
-	logDebugln("【RunKotlinLint】开始执行")
-	logDebug("  - 仓库路径：%s\n", repoPath)
-	logDebug("  - 待检查文件数：%d\n", len(diffFiles))
-	logDebugln("=====================================")
-
-	lintResults := make(map[string]string)
-
-	// 检查是否安装了ktlint
-	if _, err := exec.LookPath("ktlint"); err != nil {
-		logDebugln("⚠️【RunKotlinLint】未检测到ktlint，跳过规则检查")
-		for file := range diffFiles {
-			lintResults[file] = "【规则检查】未执行：缺少ktlint环境"
-		}
-		return lintResults
-	}
-
-	for file := range diffFiles {
-		logDebug("ℹ️【RunKotlinLint】检查文件：%s\n", file)
-		cmd := exec.Command("ktlint", file)
-		output, err := cmd.CombinedOutput()
-
-		if err != nil {
-			logDebug("⚠️【RunKotlinLint】文件%s检查失败：%v\n", file, err)
-			lintResults[file] = fmt.Sprintf("【规则检查】执行失败：%s，输出：%s", err.Error(), string(output))
-			continue
-		}
-
-		if string(output) == "" {
-			logDebug("✅【RunKotlinLint】文件%s未发现违规问题\n", file)
-			lintResults[file] = "【规则检查】未发现违规问题"
-		} else {
-			logDebug("⚠️【RunKotlinLint】文件%s发现违规问题：%s\n", file, string(output))
-			lintResults[file] = fmt.Sprintf("【规则检查】发现问题：%s", string(output))
-		}
-	}
-
-	return lintResults
-}
-
-// GetReviewProcess 根据语言获取对应的评审流程实现
-func GetReviewProcess(language string) ReviewProcess {
-	switch strings.ToLower(language) {
-	case "java":
-		return &JavaReviewProcess{}
-	case "python":
-		return &PythonReviewProcess{}
-	case "javascript", "js", "typescript", "ts", "tsx":
-		return &JavaScriptReviewProcess{}
-	case "swift":
-		return &SwiftReviewProcess{}
-	case "kotlin", "kt":
-		return &KotlinReviewProcess{}
-	case "golang", "go", "":
-		fallthrough
-	default:
-		return &GolangReviewProcess{}
-	}
-}
-
-func (j *JavaScriptReviewProcess) FilterFiles(diffItems []DiffItem) map[string]string {
-	diffMap := make(map[string]string)
-	for _, diffItem := range diffItems {
-		// 跳过二进制文件
-		if diffItem.Binary {
-			logDebug("ℹ️【GetMRDiff】跳过二进制文件：%s\n", diffItem.NewPath)
-			continue
-		}
-
-		// 确定文件路径（兼容重命名/删除场景）
-		filePath := diffItem.NewPath
-		if filePath == "" {
-			filePath = diffItem.OldPath
-		}
-
-		// 确定文件状态
-		var status string
-		if diffItem.NewFile {
-			status = "added"
-		} else if diffItem.DeletedFile {
-			status = "removed"
-		} else if diffItem.RenamedFile {
-			status = "renamed"
-		} else {
-			status = "modified"
-		}
-
-		// 仅保留新增/修改的JavaScript/TypeScript文件
-		if (status == "added" || status == "modified") && (strings.HasSuffix(filePath, ".js") || strings.HasSuffix(filePath, ".jsx") || strings.HasSuffix(filePath, ".ts") || strings.HasSuffix(filePath, ".tsx")) {
-			diffMap[filePath] = diffItem.Diff
-			logDebug("✅【GetMRDiff】检测到需评审文件：%s（状态：%s）\n", filePath, status)
-		}
-	}
-	return diffMap
-}
-
-func (k *KotlinReviewProcess) FilterFiles(diffItems []DiffItem) map[string]string {
-	diffMap := make(map[string]string)
-	for _, diffItem := range diffItems {
-		// 跳过二进制文件
-		if diffItem.Binary {
-			logDebug("ℹ️【GetMRDiff】跳过二进制文件：%s\n", diffItem.NewPath)
-			continue
-		}
-
-		// 确定文件路径（兼容重命名/删除场景）
-		filePath := diffItem.NewPath
-		if filePath == "" {
-			filePath = diffItem.OldPath
-		}
-
-		// 确定文件状态
-		var status string
-		if diffItem.NewFile {
-			status = "added"
-		} else if diffItem.DeletedFile {
-			status = "removed"
-		} else if diffItem.RenamedFile {
-			status = "renamed"
-		} else {
-			status = "modified"
-		}
-
-		// 仅保留新增/修改的Kotlin文件
-		if (status == "added" || status == "modified") && strings.HasSuffix(filePath, ".kt") {
-			diffMap[filePath] = diffItem.Diff
-			logDebug("✅【GetMRDiff】检测到需评审文件：%s（状态：%s）\n", filePath, status)
-		}
-	}
-	return diffMap
-}
-
 func maskSensitive(str string) string {
 	if len(str) <= 6 {
 		return "****"
@@ -940,7 +257,7 @@ func maskSensitive(str string) string {
 }
 
 // 1. 拉取MR变更代码
-func GetMRDiff(config Config, process ReviewProcess) (map[string]string, *CommitInfo, error) {
+func GetMRDiff(config Config, process ReviewProcess) (map[string]string, []FileChange, *CommitInfo, error) {
 	logDebugln("=====================================")
 	logDebugln("【GetMRDiff】开始执行，配置详情：")
 	logDebug("  - YunxiaoToken: %s\n", maskSensitive(config.YunxiaoToken))
@@ -970,18 +287,18 @@ func GetMRDiff(config Config, process ReviewProcess) (map[string]string, *Commit
 
 	if err != nil {
 		logDebug("❌【GetMRDiff】云效OpenAPI请求失败：%v\n", err)
-		return nil, nil, fmt.Errorf("云效OpenAPI请求失败：%w", err)
+		return nil, nil, nil, fmt.Errorf("云效OpenAPI请求失败：%w", err)
 	}
 	if resp.StatusCode() != 200 {
 		logDebug("❌【GetMRDiff】云效OpenAPI返回异常状态码：%d，响应内容：%s\n", resp.StatusCode(), string(resp.Body()))
-		return nil, nil, fmt.Errorf("云效OpenAPI返回异常状态码：%d，响应内容：%s",
+		return nil, nil, nil, fmt.Errorf("云效OpenAPI返回异常状态码：%d，响应内容：%s",
 			resp.StatusCode(), string(resp.Body()))
 	}
 
 	var compareResp CompareResponseV2
 	if err := json.Unmarshal(resp.Body(), &compareResp); err != nil {
 		logDebug("❌【GetMRDiff】解析云效OpenAPI响应失败：%v，响应内容：%s\n", err, string(resp.Body()))
-		return nil, nil, fmt.Errorf("解析云效OpenAPI响应失败：%w，响应内容：%s", err, string(resp.Body()))
+		return nil, nil, nil, fmt.Errorf("解析云效OpenAPI响应失败：%w，响应内容：%s", err, string(resp.Body()))
 	}
 
 	logDebug("✅【GetMRDiff】成功拉取响应，共检测到%d个变更文件\n", len(compareResp.Diffs))
@@ -991,8 +308,9 @@ func GetMRDiff(config Config, process ReviewProcess) (map[string]string, *Commit
 	if len(compareResp.Commits) > 0 {
 		commit := compareResp.Commits[0]
 		commitInfo = &CommitInfo{
-			AuthorName: commit.AuthorName,
-			Message:    commit.Message,
+			AuthorName:  commit.AuthorName,
+			AuthorEmail: commit.AuthorEmail,
+			Message:     commit.Message,
 		}
 	}
 
@@ -1010,214 +328,151 @@ func GetMRDiff(config Config, process ReviewProcess) (map[string]string, *Commit
 		})
 	}
 
+	pathFilter, err := newPathFilter(config.File.Filter)
+	if err != nil {
+		logDebug("❌【GetMRDiff】%v\n", err)
+		return nil, nil, nil, err
+	}
+	diffItems = pathFilter.Apply(diffItems)
 	diffMap := process.FilterFiles(diffItems)
+	changes := collectFileChanges(diffItems, process)
 
 	if len(diffMap) == 0 {
 		logDebug("ℹ️【GetMRDiff】未检测到新增/修改的%s文件，无需评审\n", process.GetFileExtension())
-		return diffMap, commitInfo, nil
+		return diffMap, changes, commitInfo, nil
 	}
 	logDebug("📌【GetMRDiff】共筛选出%d个需评审的%s文件\n", len(diffMap), process.GetFileExtension())
-	return diffMap, commitInfo, nil
+	return diffMap, changes, commitInfo, nil
 }
 
-// 2. 执行golangci-lint规则检查
-func RunGolangciLint(repoPath string, diffFiles map[string]string) map[string]string {
-	logDebugln("\n=====================================")
-	logDebugln("【RunGolangciLint】开始执行")
-	logDebug("  - 仓库路径：%s\n", repoPath)
-	logDebug("  - 待检查文件数：%d\n", len(diffFiles))
-	logDebugln("=====================================")
-
-	lintResults := make(map[string]string)
+// GetMRDetail 查询MR详情（作者、评审人、目标分支），用于钉钉通知@相关人员
+func GetMRDetail(config Config) (*MRInfo, error) {
+	logDebug("🔍【GetMRDetail】开始查询MR详情，MRID：%d\n", config.MRID)
 
-	if _, err := exec.LookPath("golangci-lint"); err != nil {
-		logDebugln("⚠️【RunGolangciLint】未检测到golangci-lint，跳过规则检查")
-		for file := range diffFiles {
-			lintResults[file] = "【规则检查】未执行：缺少golangci-lint环境"
-		}
-		return lintResults
+	resp, err := client.R().
+		SetHeader("x-yunxiao-token", config.YunxiaoToken).
+		SetHeader("Accept", "application/json").
+		Get(fmt.Sprintf("https://%s/oapi/v1/codeup/organizations/%s/repositories/%d/changeRequests/%d",
+			config.CodeupDomain, config.OrgID, config.RepoID, config.MRID))
+	if err != nil {
+		return nil, fmt.Errorf("查询MR详情请求失败：%w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return nil, fmt.Errorf("查询MR详情返回异常状态码：%d，响应内容：%s", resp.StatusCode(), string(resp.Body()))
 	}
 
-	for file := range diffFiles {
-		logDebug("ℹ️【RunGolangciLint】检查文件：%s\n", file)
-		cmd := exec.Command("bash", "-c",
-			fmt.Sprintf("cd %s && golangci-lint run --new-from-rev=origin/main %s", repoPath, file))
-		output, err := cmd.CombinedOutput()
-
-		if err != nil {
-			logDebug("⚠️【RunGolangciLint】文件%s检查失败：%v\n", file, err)
-			lintResults[file] = fmt.Sprintf("【规则检查】执行失败：%s，输出：%s", err.Error(), string(output))
-			continue
-		}
-
-		if string(output) == "" {
-			logDebug("✅【RunGolangciLint】文件%s未发现违规问题\n", file)
-			lintResults[file] = "【规则检查】未发现违规问题"
-		} else {
-			logDebug("⚠️【RunGolangciLint】文件%s发现违规问题：%s\n", file, string(output))
-			lintResults[file] = fmt.Sprintf("【规则检查】发现问题：%s", string(output))
-		}
+	var detail struct {
+		Author struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"author"`
+		Reviewers []struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+		} `json:"reviewers"`
+		TargetBranch string `json:"targetBranch"`
+	}
+	if err := json.Unmarshal(resp.Body(), &detail); err != nil {
+		return nil, fmt.Errorf("解析MR详情失败：%w，响应内容：%s", err, string(resp.Body()))
 	}
 
-	return lintResults
+	mrInfo := &MRInfo{TargetBranch: detail.TargetBranch}
+	if detail.Author.Name != "" || detail.Author.Email != "" {
+		mrInfo.Author = &MRUser{Name: detail.Author.Name, Email: detail.Author.Email}
+	}
+	for _, reviewer := range detail.Reviewers {
+		mrInfo.Reviewers = append(mrInfo.Reviewers, MRUser{Name: reviewer.Name, Email: reviewer.Email})
+	}
+	logDebug("✅【GetMRDetail】目标分支：%s，评审人数：%d\n", mrInfo.TargetBranch, len(mrInfo.Reviewers))
+	return mrInfo, nil
 }
 
 // 3. 调用阿里云百炼API进行AI代码评审
-func AICodeReview(config Config, diffFiles map[string]string, lintResults map[string]string, process ReviewProcess) (string, []string, []string, error) {
+func AICodeReview(config Config, diffFiles map[string]string, changes []FileChange, lintResults map[string]string, process ReviewProcess) (string, []BlockIssue, error) {
 	logDebugln("\n=====================================")
 	logDebugln("【AICodeReview】开始执行")
 	logDebug("  - 待评审文件数：%d\n", len(diffFiles))
 	logDebugln("=====================================")
 
-	// 使用ReviewProcess接口获取prompt
-	prompt := process.GetPrompt(diffFiles, lintResults)
-
-	// 使用配置的模型名称，如果没有指定则使用默认值
-	modelName := "qwen3-coder-plus"
-	if config.Model != "" {
-		modelName = config.Model
-	}
-
-	requestBody := map[string]interface{}{
-		"model": modelName,
-		"input": map[string]interface{}{
-			"messages": []map[string]interface{}{
-				{
-					"role":    "user",
-					"content": prompt,
-				},
-			},
-		},
-		"parameters": map[string]interface{}{
-			"max_new_tokens": 9999,
-			"temperature":    0.2,
-			"top_p":          0.9,
-		},
-	}
-
-	requestBodyJSON, err := json.MarshalIndent(requestBody, "", "  ")
+	// 根据ReviewProcess对应的prompt模板渲染prompt，注入团队自定义规则和输出语言要求
+	rules := applicableRules(config.File.Rules, config.Language, diffFiles)
+	logDebug("ℹ️【AICodeReview】适用的团队自定义规则数：%d\n", len(rules))
+	input := PromptInput{
+		DiffFiles:   diffFiles,
+		Changes:     changes,
+		LintResults: lintResults,
+		Rules:       rules,
+		Locale:      config.Locale,
+		Language:    languageDisplayName(config.Language),
+		Dimensions:  languageDimensions(process),
+	}
+	// 按数据治理配置排除的文件不发送给模型
+	excluded := redactor.ExcludedFiles(diffFiles)
+	if len(excluded) > 0 {
+		fmt.Printf("ℹ️【AICodeReview】%d个文件按redaction.exclude_paths配置不发送给模型：%s\n", len(excluded), strings.Join(excluded, ", "))
+	}
+	files := promptFiles(input)
+	if len(files) == 0 {
+		logDebugln("ℹ️【AICodeReview】没有可发送给模型的文件，跳过AI评审")
+		return "", nil, nil
+	}
+	prompt, err := prompts.Render(process.GetPromptName(), input)
 	if err != nil {
-		logDebug("❌【AICodeReview】构造请求体JSON失败：%v\n", err)
-		return "", nil, nil, fmt.Errorf("构造请求体JSON失败：%w", err)
+		logDebug("❌【AICodeReview】渲染prompt失败：%v\n", err)
+		return "", nil, err
 	}
-	logDebug("ℹ️【AICodeReview】构造的请求体：\n%s\n", string(requestBodyJSON))
-
-	logDebugln("ℹ️【AICodeReview】开始调用百炼原生API...")
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetHeader("Authorization", fmt.Sprintf("Bearer %s", config.BaichuanAPIKey)).
-		SetBody(requestBody).
-		Post("https://dashscope.aliyuncs.com/api/v1/services/aigc/text-generation/generation")
 
-	if err != nil {
-		logDebug("❌【AICodeReview】百炼API调用失败：%v\n", err)
-		return "", nil, nil, fmt.Errorf("百炼API调用失败：%w", err)
-	}
-
-	logDebug("ℹ️【AICodeReview】百炼API响应状态码：%d\n", resp.StatusCode())
-	logDebug("ℹ️【AICodeReview】百炼API响应内容：%s\n", string(resp.Body()))
-
-	var aiResp struct {
-		Output struct {
-			Choices []struct {
-				Message struct {
-					Content string `json:"content"`
-					Role    string `json:"role"`
-				} `json:"message"`
-				FinishReason string `json:"finish_reason"`
-			} `json:"choices"`
-		} `json:"output"`
-		Usage struct {
-			TotalTokens  int `json:"total_tokens"`
-			OutputTokens int `json:"output_tokens"`
-			InputTokens  int `json:"input_tokens"`
-		} `json:"usage"`
-		RequestID string `json:"request_id"`
-		Code      string `json:"code"`
-		Message   string `json:"message"`
-	}
-	if err := json.Unmarshal(resp.Body(), &aiResp); err != nil {
-		logDebug("❌【AICodeReview】解析百炼API响应失败：%v，响应内容：%s\n", err, string(resp.Body()))
-		return "", nil, nil, fmt.Errorf("解析百炼API响应失败：%w，响应内容：%s", err, string(resp.Body()))
-	}
-
-	if aiResp.Code != "" {
-		logDebug("❌【AICodeReview】百炼API返回业务错误：code=%s, message=%s\n", aiResp.Code, aiResp.Message)
-		return "", nil, nil, fmt.Errorf("百炼API业务错误：%s - %s", aiResp.Code, aiResp.Message)
+	// 多次采样时只保留多数采样中都出现的问题（self-consistency），降低单次调用的误报
+	samples := config.Samples
+	if samples < 1 {
+		samples = 1
 	}
-
 	var aiResult string
-	if len(aiResp.Output.Choices) > 0 {
-		aiResult = strings.TrimSpace(aiResp.Output.Choices[0].Message.Content)
-	}
-	logDebug("✅【AICodeReview】百炼API调用成功，RequestID：%s\n", aiResp.RequestID)
-	logDebug("ℹ️【AICodeReview】Token使用情况：Total=%d, Input=%d, Output=%d\n",
-		aiResp.Usage.TotalTokens, aiResp.Usage.InputTokens, aiResp.Usage.OutputTokens)
-	logDebug("ℹ️【AICodeReview】AI评审结果：%s\n", aiResult)
-
-	// 提取阻断级和高级别问题
-	var blockIssues []string
-	var highIssues []string
-	if aiResult != "✅ 未发现任何问题" {
-		lines := strings.Split(aiResult, "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line == "" {
-				continue
-			}
-			if strings.Contains(line, fmt.Sprintf("[%s]", LevelBlock)) {
-				blockIssues = append(blockIssues, line)
-				logDebug("❌【AICodeReview】检测到阻断级问题：%s\n", line)
-			} else if strings.Contains(line, fmt.Sprintf("[%s]", LevelHigh)) {
-				highIssues = append(highIssues, line)
-				logDebug("⚠️【AICodeReview】检测到高级别问题：%s\n", line)
-			}
+	var sampleIssues [][]BlockIssue
+	for n := 1; n <= samples; n++ {
+		record := AuditRecord{Model: config.Model, Purpose: AuditPurposeReview, Files: auditFiles(files), Excluded: excluded}
+		if err := auditModelCall(config, record, prompt); err != nil {
+			return "", nil, err
+		}
+		content, err := callModel(config, config.Model, prompt, reviewTemperature)
+		if err != nil {
+			return "", nil, err
+		}
+		logDebug("ℹ️【AICodeReview】第%d/%d次采样评审结果：%s\n", n, samples, content)
+		if n == 1 {
+			aiResult = content
 		}
+		sampleIssues = append(sampleIssues, parseFindings(content))
 	}
 
-	logDebug("📊【AICodeReview】AI评审完成，检测到%d个阻断级问题，%d个高级别问题.\n", len(blockIssues), len(highIssues))
-	return aiResult, blockIssues, highIssues, nil
+	// 解析为规范化的问题列表，命中自定义规则的问题以规则配置的等级为准
+	issues := sampleIssues[0]
+	if samples > 1 {
+		issues = voteFindings(sampleIssues)
+	}
+	applyRuleSeverity(issues, rules)
+	levelCount := make(map[string]int)
+	for _, issue := range issues {
+		levelCount[issue.Level]++
+	}
+	logDebug("📊【AICodeReview】AI评审完成，共%d个问题：block=%d, high=%d, medium=%d, suggest=%d\n",
+		len(issues), levelCount[LevelBlock], levelCount[LevelHigh], levelCount[LevelMedium], levelCount[LevelSuggest])
+	return aiResult, issues, nil
 }
 
 // 4. 将评审结果评论到Codeup MR
-func CommentMR(config Config, reviewResult string) error {
+func CommentMR(config Config, renderer *TemplateRenderer, data TemplateData) error {
 	logDebugln("\n=====================================")
 	logDebugln("【CommentMR】开始执行")
 	logDebug("  - MRID：%d\n", config.MRID)
 	logDebugln("=====================================")
 
-	// 根据语言类型获取对应的文件扩展名描述
-	var langDesc string
-	switch config.Language {
-	case "java":
-		langDesc = "Java"
-	case "python":
-		langDesc = "Python"
-	case "javascript", "js", "typescript", "ts", "tsx":
-		langDesc = "JavaScript/TypeScript"
-	case "swift":
-		langDesc = "Swift"
-	case "kotlin", "kt":
-		langDesc = "Kotlin"
-	case "golang", "go", "":
-		fallthrough
-	default:
-		langDesc = "Go"
+	commentBody, err := renderer.Render(TemplateCommentMR, data)
+	if err != nil {
+		logDebug("❌【CommentMR】渲染评论模板失败：%v\n", err)
+		return err
 	}
 
-	commentBody := fmt.Sprintf(`
-### 🤖 AI Code Review 结果（MR #%d）
-#### 评审范围：提交ID %s → %s 变更的%s文件
-#### 问题等级说明：
-- [%s]：阻断级，必须修复才能合并
-- [%s]：高风险，建议优先修复
-- [%s]：中风险，建议修复
-- [%s]：优化建议，不强制
-
----
-%s`, config.MRID, config.FromCommit, config.ToCommit, langDesc,
-		LevelBlock, LevelHigh, LevelMedium, LevelSuggest, reviewResult)
-
 	resp, err := client.R().
 		SetHeader("x-yunxiao-token", config.YunxiaoToken).
 		SetHeader("Content-Type", "application/json").
@@ -1250,50 +505,25 @@ func CommentMR(config Config, reviewResult string) error {
 }
 
 // 5. 将评审结果评论到Codeup Commit
-func CommentCommit(config Config, reviewResult string) error {
+func CommentCommit(config Config, renderer *TemplateRenderer, data TemplateData) error {
 	logDebugln("\n=====================================")
 	logDebugln("【CommentCommit】开始执行")
 	logDebug("  - OrgID：%s\n", config.OrgID)
 	logDebug("  - RepoID：%d\n", config.RepoID)
 	logDebug("  - CommitID：%s\n", config.CommitID)
-	logDebug("  - reviewResult：%s\n", reviewResult)
+	logDebug("  - reviewResult：%s\n", data.RawResult)
 	logDebugln("=====================================")
 
-	if reviewResult == "" {
+	if data.RawResult == "" {
 		logDebugln("ℹ️【CommentCommit】AI评审结果为空，跳过评论提交")
 		return nil
 	}
-	// 根据语言类型获取对应的文件扩展名描述
-	var langDesc string
-	switch config.Language {
-	case "java":
-		langDesc = "Java"
-	case "python":
-		langDesc = "Python"
-	case "javascript", "js", "typescript", "ts", "tsx":
-		langDesc = "JavaScript/TypeScript"
-	case "swift":
-		langDesc = "Swift"
-	case "kotlin", "kt":
-		langDesc = "Kotlin"
-	case "golang", "go", "":
-		fallthrough
-	default:
-		langDesc = "Go"
-	}
 
-	commentBody := fmt.Sprintf(`
-### 🤖 AI Code Review 结果（Commit %s）
-#### 评审范围：提交ID %s → %s 变更的%s文件
-#### 问题等级说明：
-- [%s]：阻断级，必须修复
-- [%s]：高风险，建议优先修复
-- [%s]：中风险，建议修复
-- [%s]：优化建议，不强制
-
----
-%s`, config.CommitID, config.FromCommit, config.ToCommit, langDesc,
-		LevelBlock, LevelHigh, LevelMedium, LevelSuggest, reviewResult)
+	commentBody, err := renderer.Render(TemplateCommentCommit, data)
+	if err != nil {
+		logDebug("❌【CommentCommit】渲染评论模板失败：%v\n", err)
+		return err
+	}
 
 	resp, err := client.R().
 		SetHeader("x-yunxiao-token", config.YunxiaoToken).
@@ -1346,7 +576,9 @@ func printUsage() {
 =====================***=======================
 功能：自动拉取Codeup MR/Commit的代码变更，执行静态检查，调用阿里云百炼AI评审，
       支持将评审结果评论到MR/Commit，阻断级问题直接终止流程。
-      支持多种编程语言：Golang/Java/Python/JavaScript/Swift/Kotlin
+      支持多种编程语言：Golang/Java/Python/JavaScript/TypeScript/Swift/Kotlin/C/C++/Rust/PHP/C#/Ruby/Dart/SQL/Shell
+      以及Dockerfile/Kubernetes/Helm/Terraform/CI流水线等基础设施代码
+      也可在配置文件的languages中声明新语言或覆盖内置语言（后缀、prompt模板、检查工具），无需修改代码
 
 📦 安装方式：
   go install github.com/konglong87/airvw@latest
@@ -1369,14 +601,37 @@ func printUsage() {
     --comment-target string   评论目标（可选：mr/commit/空，空则不评论）
     --mr-id int               MR的ID（comment-target=mr时必填）
     --commit-id string        Commit的hash（comment-target=commit时必填）
-    --language string         评审语言（默认：golang，可选：golang/java/python/javascript/swift/kotlin）
+    --language string         评审语言（默认：golang，可选：golang/java/python/javascript/typescript/swift/kotlin/cpp/rust/php/csharp/ruby/dart/sql/shell/dockerfile/kubernetes/terraform/ci/dependencies，或配置文件languages中声明的语言）
     --model string            AI模型名称（默认：qwen3-coder-plus）
     --dingtalk-token string   钉钉机器人Token（可选）
     --dingtalk-secret string   钉钉机器人Secret（可选）
     --enable-dingtalk         是否启用钉钉通知（默认：false）
     --max-issues int          钉钉通知中显示的最大问题数量（默认：10）
+    --target-branch string    MR目标分支（可选，未指定时从MR详情获取，用于判断是否@所有人）
+    --config string           配置文件路径（可选，默认读取当前目录下的.airvw.json）
+    --notify string           启用的通知渠道名称，逗号分隔（可选，默认启用全部已配置渠道）
+    --locale string           评论/通知语言（可选，默认：zh，可选：zh/en）
+    --notify-retries int      通知发送失败后的重试次数（默认：2）
+    --fail-on-notify-error    所有通知均视为必需，任一通知重试后仍失败时以非0退出（默认：false）
+    --report-file string      评审结果JSON报告输出路径（可选）
+    --prompt-dir string       prompt模板覆盖目录（可选，目录下的base.tmpl/<语言>.tmpl叠加在内置模板之上）
+    --samples int             评审采样次数，大于1时只保留过半数采样中出现的问题（默认：1）
+    --verify                  将block/high级问题连同相关代码交给模型逐条复核，确认/降级/驳回（默认：false）
+    --verify-model string     复核使用的模型（可选，默认与--model相同，可指定另一个模型交叉验证）
+    --min-confidence float    block/high级问题参与卡点的最低置信度（0~1），低于该值只提示不卡点（默认：不限制）
+    --advisory                advisory（试运行）模式：违反卡点策略时只报告不以非0退出（默认：false）
+    --baseline string         基线文件路径（可选，默认读取当前目录下的.airvw-baseline.json，命中的存量问题不参与卡点）
+    --profile string          评审模式（默认：default，可选：default/security，security模式的问题标注CWE/OWASP分类并追加安全扫描工具）
+    --sarif-file string       SARIF格式评审结果输出路径（可选，可上传到代码扫描平台）
+    --coverage-file string    覆盖率文件路径（可选，支持Go coverprofile、JaCoCo XML、lcov），指定后开启测试缺口检查，报告未被测试覆盖的新增行
+    --audit-log string        模型调用审计日志路径（可选，记录每次发送给模型的文件和字节数，也可在配置文件redaction.audit_log中设置）
+    --debug                   输出调试日志（默认：false）
     --help                    显示此帮助信息
 
+🧰 子命令：
+  airvw prompt render         打印给定diff实际发送给模型的prompt（git diff | airvw prompt render --language golang）
+  airvw baseline create       根据评审报告生成基线文件（airvw baseline create --report airvw-report.json）
+
 💡 使用示例：
   1. 仅执行AI评审（不评论）：
      airvw --yunxiao-token pt-xxx --org-id 67aaaaaaaaaa --repo-id 5023797 \
@@ -1418,21 +673,40 @@ func printUsage() {
            --from-commit xxxxxx --to-commit xxxxxx --baichuan-key sk-xxx \
            --enable-dingtalk --dingtalk-token xxx --dingtalk-secret xxx
 
+  9. 安全评审并输出SARIF：
+     airvw --yunxiao-token pt-xxx --org-id 67aaaaaaaaaa --repo-id 5023797 \
+           --from-commit xxxxxx --to-commit xxxxxx --baichuan-key sk-xxx \
+           --profile security --sarif-file airvw.sarif
+
+  10. 评审依赖变更（漏洞库和许可证允许列表在配置文件dependencies中设置）：
+     airvw --yunxiao-token pt-xxx --org-id 67aaaaaaaaaa --repo-id 5023797 \
+           --from-commit xxxxxx --to-commit xxxxxx --baichuan-key sk-xxx \
+           --language dependencies
+
 ⚠️ 注意事项：
   1. Golang需提前安装golangci-lint（可选，未安装则跳过规则检查）
   2. Java需提前安装checkstyle（可选，未安装则跳过规则检查）
   3. Python需提前安装flake8（可选，未安装则跳过规则检查）
-  4. JavaScript需提前安装eslint（可选，未安装则跳过规则检查）
+  4. JavaScript需提前安装eslint（可选，未安装则跳过规则检查）；TypeScript优先使用仓库node_modules中的tsc（.vue文件使用vue-tsc）和eslint
   5. Swift需提前安装swiftlint（可选，未安装则跳过规则检查）
   6. Kotlin需提前安装ktlint（可选，未安装则跳过规则检查）
-  7. 百炼API Key需具备文本生成权限
-  8. 云效Token需具备Codeup MR/Commit评论权限
-  9. 仅评审新增/修改的对应语言文件，二进制文件、删除/重命名文件会被过滤
+     C/C++（clang-tidy/cppcheck）、Rust（cargo clippy）、PHP（phpstan）、C#（dotnet format）、Ruby（rubocop）、
+     Dart（dart analyze）、SQL（sqlfluff）、Shell（shellcheck）的检查工具同样可选，使用仓库自身的配置文件
+     基础设施代码的hadolint、kubeconform/kube-linter、tflint/tfsec、actionlint同样可选；
+     同一MR中的多种文件需分别以对应的--language执行评审
+  7. --profile security时追加的gosec/bandit/semgrep等安全扫描工具同样可选
+  8. 百炼API Key需具备文本生成权限
+  9. 云效Token需具备Codeup MR/Commit评论权限
+  10. 仅逐行评审新增/修改（含重命名后有改动）的对应语言文件，二进制文件会被过滤；
+      重命名和删除的文件汇总给模型，用于检查其他文件中失效的引用和被删除的公开声明
 `
 	fmt.Println(usage)
 }
 
 func main() {
+	if matched, code := runSubcommand(os.Args[1:]); matched {
+		os.Exit(code)
+	}
 	flag.Usage = printUsage
 
 	fmt.Println("🚀 开始执行AI Code Review流程...")
@@ -1449,15 +723,40 @@ func main() {
 	flag.StringVar(&config.ReviewLevel, "level", LevelBlock, "评审等级（block/high/medium/suggest）")
 	flag.StringVar(&config.CommentTarget, "comment-target", "", "评论目标：mr（评论MR）/commit（评论Commit）/空（不评论）")
 	flag.StringVar(&config.CommitID, "commit-id", "", "评论Commit时的commit hash（comment-target=commit时必填）")
-	flag.StringVar(&config.Language, "language", "golang", "评审语言：golang/java/python/javascript/swift/kotlin（默认golang）")
-	flag.StringVar(&config.Model, "model", "qwen3-coder-plus", "AI模型名称（默认qwen3-coder-plus）")
+	flag.StringVar(&config.Language, "language", "golang", "评审语言："+strings.Join(languageNames(), "/")+"，或配置文件languages中声明的语言（默认golang）")
+	flag.StringVar(&config.Model, "model", defaultModel, "AI模型名称（默认qwen3-coder-plus）")
 	flag.BoolVar(&config.Debug, "debug", false, "是否开启调试模式，默认false")
 	flag.StringVar(&config.DingTalkToken, "dingtalk-token", "", "钉钉机器人Token（可选）")
 	flag.StringVar(&config.DingTalkSecret, "dingtalk-secret", "", "钉钉机器人Secret（可选）")
 	flag.BoolVar(&config.EnableDingTalk, "enable-dingtalk", false, "是否启用钉钉通知，默认false")
 	flag.IntVar(&config.MaxIssues, "max-issues", 10, "钉钉通知中显示的最大问题数量，默认10")
+	flag.StringVar(&config.TargetBranch, "target-branch", "", "MR目标分支（可选，未指定时从MR详情获取）")
+	flag.StringVar(&config.ConfigFile, "config", "", "配置文件路径（可选，默认读取当前目录下的.airvw.json）")
+	flag.StringVar(&config.Locale, "locale", "", "评论/通知语言：zh/en（可选，默认zh，也可在配置文件中设置）")
+	flag.StringVar(&config.Notify, "notify", "", "启用的通知渠道名称，逗号分隔（可选，默认启用全部已配置渠道）")
+	flag.IntVar(&config.NotifyRetries, "notify-retries", 2, "通知发送失败后的重试次数，默认2")
+	flag.BoolVar(&config.FailOnNotifyError, "fail-on-notify-error", false, "将所有通知视为必需，任一通知重试后仍失败时以非0退出，默认false")
+	flag.StringVar(&config.ReportFile, "report-file", "", "评审结果JSON报告输出路径（可选）")
+	flag.StringVar(&config.PromptDir, "prompt-dir", "", "prompt模板覆盖目录（可选，目录下的base.tmpl/<语言>.tmpl叠加在内置模板之上）")
+	flag.StringVar(&config.Baseline, "baseline", "", "基线文件路径（可选，默认读取当前目录下的.airvw-baseline.json），命中基线的存量问题不参与卡点")
+	flag.IntVar(&config.Samples, "samples", 1, "评审采样次数，大于1时只保留过半数采样中出现的问题，默认1")
+	flag.BoolVar(&config.Verify, "verify", false, "是否将block/high级问题交给模型逐条复核（确认/降级/驳回），默认false")
+	flag.StringVar(&config.VerifyModel, "verify-model", "", "复核使用的模型（可选，默认与--model相同）")
+	flag.Float64Var(&config.MinConfidence, "min-confidence", 0, "block/high级问题参与卡点的最低置信度（0~1），低于该值只提示不卡点（可选，也可在配置文件policy.min_confidence中设置）")
+	flag.BoolVar(&config.Advisory, "advisory", false, "advisory（试运行）模式：违反卡点策略时只报告不以非0退出，默认false")
+	flag.StringVar(&config.Profile, "profile", ProfileDefault, "评审模式：default（通用评审）/security（安全评审，问题标注CWE和OWASP分类，并追加gosec/bandit/semgrep等安全扫描）")
+	flag.StringVar(&config.SARIFFile, "sarif-file", "", "SARIF格式评审结果输出路径（可选，可上传到代码扫描平台）")
+	flag.StringVar(&config.CoverageFile, "coverage-file", "", "覆盖率文件路径（可选，支持Go coverprofile、JaCoCo XML、lcov），指定后检查变更的新增行是否被测试覆盖")
+	flag.StringVar(&config.AuditLog, "audit-log", "", "模型调用审计日志路径（可选，记录每次发送给模型的文件和字节数，也可在配置文件redaction.audit_log中设置）")
 	flag.Parse()
 
+	levelExplicit := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "level" {
+			levelExplicit = true
+		}
+	})
+
 	debugMode = config.Debug
 
 	if len(os.Args) == 2 && (os.Args[1] == "--help" || os.Args[1] == "-h") {
@@ -1496,16 +795,62 @@ func main() {
 		missingParams = append(missingParams, "commit-id（评论Commit时必填）")
 	}
 
+	if config.MinConfidence < 0 || config.MinConfidence > 1 {
+		fmt.Printf("❌【aiutoCR】错误：--min-confidence必须在0~1之间：%v\n", config.MinConfidence)
+		os.Exit(1)
+	}
+	if err := validateLevel(config.ReviewLevel); err != nil {
+		fmt.Printf("❌【aiutoCR】错误：--level %s\n", err)
+		os.Exit(1)
+	}
+	if err := validateProfile(config.Profile); err != nil {
+		fmt.Printf("❌【aiutoCR】错误：--profile %s\n", err)
+		os.Exit(1)
+	}
+
 	if len(missingParams) > 0 {
 		fmt.Printf("❌【aiutoCR】错误：缺少必填参数：%s\n", strings.Join(missingParams, ", "))
 		printUsage()
 		os.Exit(1)
 	}
 
-	reviewProcess := GetReviewProcess(config.Language)
-	logDebug("ℹ️【aiutoCR】使用%s语言评审流程\n", config.Language)
+	fileConfig, err := loadFileConfig(config.ConfigFile)
+	if err != nil {
+		fmt.Printf("❌【aiutoCR】加载配置文件失败：%s\n", err)
+		os.Exit(1)
+	}
+	config.File = fileConfig
+
+	applyPromptConfig(&config)
+	if err := applyRedactionConfig(&config); err != nil {
+		fmt.Printf("❌【aiutoCR】脱敏配置错误：%s\n", err)
+		os.Exit(1)
+	}
+	applyDependencyConfig(&config)
+	registerLanguages(config.File.Languages)
+	renderer := newTemplateRenderer(config.Locale, config.File.Templates)
 
-	diffFiles, commitInfo, err := GetMRDiff(config, reviewProcess)
+	notifiers, err := buildNotifiers(config, renderer)
+	if err != nil {
+		fmt.Printf("❌【aiutoCR】通知渠道配置错误：%s\n", err)
+		os.Exit(1)
+	}
+
+	policy, err := newPolicy(config, levelExplicit)
+	if err != nil {
+		fmt.Printf("❌【aiutoCR】卡点策略配置错误：%s\n", err)
+		os.Exit(1)
+	}
+	baseline, err := loadBaseline(config.Baseline)
+	if err != nil {
+		fmt.Printf("❌【aiutoCR】加载基线文件失败：%s\n", err)
+		os.Exit(1)
+	}
+
+	reviewProcess := applyProfile(GetReviewProcess(config.Language), config.Profile)
+	logDebug("ℹ️【aiutoCR】使用%s语言评审流程，评审模式：%s\n", config.Language, config.Profile)
+
+	diffFiles, changes, commitInfo, err := GetMRDiff(config, reviewProcess)
 	if err != nil {
 		fmt.Printf("❌【aiutoCR】拉取MR变更失败：%s\n", err)
 		os.Exit(1)
@@ -1517,102 +862,172 @@ func main() {
 
 	lintResults := reviewProcess.RunLint(".", diffFiles)
 
-	aiResult, blockIssues, highIssues, err := AICodeReview(config, diffFiles, lintResults, reviewProcess)
+	// 扫描新增行中的密钥和凭证，命中即为block级问题；发送给模型的prompt中密钥已被脱敏
+	secretIssues := scanSecrets(diffFiles, renderer.Locale())
+	if len(secretIssues) > 0 {
+		fmt.Printf("🔑【aiutoCR】检测到%d处疑似密钥泄漏\n", len(secretIssues))
+	}
+
+	// 内置语言的确定性检查（如依赖的已知漏洞和许可证），结果直接作为问题
+	scanIssues, err := scanFindings(reviewProcess, ".", diffFiles, renderer.Locale())
+	if err != nil {
+		fmt.Printf("❌【aiutoCR】%s检查失败：%s\n", languageDisplayName(config.Language), err)
+		os.Exit(1)
+	}
+
+	// 检查生产代码的变更是否经过测试：新增行的覆盖率，或是否同时修改了对应的测试文件
+	testGapConfig := config.File.TestGap
+	if config.CoverageFile != "" {
+		testGapConfig.Enabled = true
+		testGapConfig.CoverageFile = config.CoverageFile
+	}
+	testGapIssues, err := findTestGaps(testGapConfig, reviewProcess, ".", diffFiles, renderer.Locale())
+	if err != nil {
+		fmt.Printf("❌【aiutoCR】测试缺口检查失败：%s\n", err)
+		os.Exit(1)
+	}
+	if len(testGapIssues) > 0 {
+		fmt.Printf("🧪【aiutoCR】%d个文件的变更缺少测试\n", len(testGapIssues))
+	}
+	scanIssues = append(scanIssues, testGapIssues...)
+
+	aiResult, issues, err := AICodeReview(config, diffFiles, changes, lintResults, reviewProcess)
 	if err != nil {
 		fmt.Printf("❌【aiutoCR】AI评审失败：%s\n", err)
 		os.Exit(1)
 	}
+	issues = append(append(secretIssues, scanIssues...), issues...)
+
+	// 校验问题位置：行号修正到变更范围内，引用变更之外文件的问题不参与卡点也不出现在评论中
+	issues, unlocated := locateFindings(issues, diffFiles)
+	if len(unlocated) > 0 {
+		fmt.Printf("ℹ️【aiutoCR】%d个问题引用的文件不在本次变更中，已忽略\n", len(unlocated))
+	}
+
+	// 按源码中的airvw:ignore注释过滤问题，被忽略的问题不参与卡点也不出现在评论中
+	source := newSourceReader(".", diffFiles)
+	issues, suppressed := newSuppressor(source).Filter(issues)
+	if len(suppressed) > 0 {
+		fmt.Printf("ℹ️【aiutoCR】%d个问题被源码中的%s注释忽略\n", len(suppressed), suppressDirective)
+	}
+
+	// 钉钉通知需要@MR作者和评审人，查询失败不影响评审
+	var mrInfo *MRInfo
+	if len(notifiers) > 0 && config.MRID != 0 {
+		mrInfo, err = GetMRDetail(config)
+		if err != nil {
+			logDebug("⚠️【aiutoCR】查询MR详情失败（不终止评审）：%s\n", err)
+		}
+	}
+
+	// 命中基线的存量问题仍会展示，但不参与卡点；密钥泄漏问题始终参与卡点，不按基线标记
+	knownIssues := 0
+	for i := range issues {
+		if !issues[i].Secret && baseline.Contains(issues[i], source) {
+			issues[i].Known = true
+			knownIssues++
+		}
+	}
+
+	// 复核block/high级问题，驳回的问题不参与卡点，在JSON结果中单独列出
+	var rejected []BlockIssue
+	if config.Verify {
+		issues, rejected = verifyFindings(config, issues, source)
+		if len(rejected) > 0 {
+			fmt.Printf("ℹ️【aiutoCR】%d个问题经复核判定为误报，已驳回\n", len(rejected))
+		}
+	}
+	decision := policy.Evaluate(issues)
+	sortBlockIssues(issues)
+	shouldBlock := decision.Blocked && decision.Mode == PolicyEnforce
+
+	result := ReviewResult{
+		Status:        "success",
+		TotalIssues:   len(issues),
+		BlockIssues:   issues,
+		Message:       tr(renderer.Locale(), "msg_passed_issues", len(issues)),
+		CommitInfo:    commitInfo,
+		MRInfo:        mrInfo,
+		Model:         config.Model,
+		PromptVersion: prompts.Version(reviewProcess.GetPromptName()),
+		KnownIssues:   knownIssues,
+		Rejected:      rejected,
+		Unlocated:     unlocated,
+		Suppressed:    suppressed,
+		Policy:        &decision,
+	}
+	if decision.Blocked {
+		blockReason := decision.Reason(renderer.Locale())
+		result.BlockReason = blockReason
+		if shouldBlock {
+			result.Status = "blocked"
+			result.TotalIssues = len(decision.Issues)
+			result.BlockIssues = decision.Issues
+			sortBlockIssues(result.BlockIssues)
+			result.Message = tr(renderer.Locale(), "msg_blocked", len(decision.Issues), blockReason)
+		} else {
+			result.Message = tr(renderer.Locale(), "msg_advisory", len(decision.Issues), blockReason)
+		}
+	}
 
 	// 步骤4：仅当评论目标为mr/commit时，执行评论操作；否则跳过
+	var sideEffects []SideEffect
 	var commentErr error
+	commentData := renderer.NewData(config, result, issues, aiResult, 0)
 	switch config.CommentTarget {
 	case "mr":
-		commentErr = CommentMR(config, aiResult)
+		commentErr = CommentMR(config, renderer, commentData)
+		sideEffects = append(sideEffects, newSideEffect(EffectComment, "mr", false, 1, commentErr))
 	case "commit":
-		commentErr = CommentCommit(config, aiResult)
+		commentErr = CommentCommit(config, renderer, commentData)
+		sideEffects = append(sideEffects, newSideEffect(EffectComment, "commit", false, 1, commentErr))
 	default:
 		logDebugln("ℹ️【aiutoCR】未指定有效评论目标（mr/commit），跳过评论操作")
 	}
 	if commentErr != nil {
-		logDebug("⚠️【aiutoCR】评论%s失败（不终止评审）：%s\n", config.CommentTarget, commentErr)
+		fmt.Printf("⚠️【aiutoCR】评论%s失败（不终止评审）：%s\n", config.CommentTarget, commentErr)
 	}
 
-	var shouldBlock bool
-	var blockReason string
-	var blockList []string
+	// 阻断或存在问题时发送通知（包括建议级），评审通过且无问题时不打扰
+	if shouldBlock || len(issues) > 0 {
+		sideEffects = append(sideEffects, notifyAll(notifiers, result, config.NotifyRetries)...)
+	}
+	result.SideEffects = sideEffects
 
-	if config.ReviewLevel == LevelBlock && len(blockIssues) > 0 {
-		shouldBlock = true
-		blockReason = "阻断级"
-		blockList = blockIssues
-	} else if config.ReviewLevel == LevelHigh && (len(blockIssues) > 0 || len(highIssues) > 0) {
-		shouldBlock = true
-		blockReason = "高级别"
-		blockList = append(blockIssues, highIssues...)
+	if config.SARIFFile != "" {
+		sarifErr := writeSARIF(config.SARIFFile, config, result, issues)
+		result.SideEffects = append(result.SideEffects, newSideEffect(EffectReport, config.SARIFFile, false, 1, sarifErr))
+		if sarifErr != nil {
+			fmt.Printf("⚠️【aiutoCR】写入SARIF文件失败：%s\n", sarifErr)
+		}
+	}
+	if config.ReportFile != "" {
+		reportErr := writeReport(config.ReportFile, result)
+		result.SideEffects = append(result.SideEffects, newSideEffect(EffectReport, config.ReportFile, false, 1, reportErr))
+		if reportErr != nil {
+			fmt.Printf("⚠️【aiutoCR】写入评审报告失败：%s\n", reportErr)
+		}
 	}
 
 	if shouldBlock {
-		logDebug("\n❌【aiutoCR】检测到%d个%s问题，终止流程！\n", len(blockList), blockReason)
-		formattedIssues := formatBlockIssues(blockList)
-		result := ReviewResult{
-			Status:      "blocked",
-			TotalIssues: len(blockList),
-			BlockReason: blockReason,
-			BlockIssues: formattedIssues,
-			Message:     fmt.Sprintf("检测到%d个%s问题，终止流程", len(blockList), blockReason),
-			CommitInfo:  commitInfo,
-			Model:       config.Model,
-		}
+		logDebug("\n❌【aiutoCR】%s\n", result.Message)
 		fmt.Println("\n======= ********** [代码问题详情] ********** =======")
 		printJSONResult(result)
-
-		// 发送钉钉通知
-		if config.EnableDingTalk {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			DingDingRemind(config.DingTalkToken, config.DingTalkSecret, string(jsonData), config.MaxIssues)
-		}
-
+		printSideEffectSummary(result.SideEffects)
 		os.Exit(1)
 	}
-	// 即使评审通过（不阻塞），用户也能看到AI评审提供的所有建议结果，而不仅仅是看到"评审通过"的提示
-	// 解析AI评审结果中的所有问题（包括建议级）
-	var allIssues []string
-	if aiResult != "✅ 未发现任何问题" {
-		lines := strings.Split(aiResult, "\n")
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line == "" {
-				continue
-			}
-			// 提取所有等级的问题
-			if strings.Contains(line, fmt.Sprintf("[%s]", LevelBlock)) ||
-				strings.Contains(line, fmt.Sprintf("[%s]", LevelHigh)) ||
-				strings.Contains(line, fmt.Sprintf("[%s]", LevelMedium)) ||
-				strings.Contains(line, fmt.Sprintf("[%s]", LevelSuggest)) {
-				allIssues = append(allIssues, line)
-			}
-		}
-	}
 
 	// 显示任何问题（包括建议级）
-	if len(allIssues) > 0 {
-		formattedIssues := formatBlockIssues(allIssues)
-		result := ReviewResult{
-			Status:      "success",
-			TotalIssues: len(allIssues),
-			BlockIssues: formattedIssues,
-			Message:     fmt.Sprintf("评审通过，发现%d个非阻塞问题", len(allIssues)),
-			CommitInfo:  commitInfo,
-			Model:       config.Model,
-		}
+	if len(issues) > 0 {
 		fmt.Println("\n======= ********** [AI评审建议详情] ********** =======")
 		printJSONResult(result)
-
-		// 发送钉钉通知
-		if config.EnableDingTalk {
-			jsonData, _ := json.MarshalIndent(result, "", "  ")
-			DingDingRemind(config.DingTalkToken, config.DingTalkSecret, string(jsonData), config.MaxIssues)
-		}
+	}
+	if decision.Blocked {
+		fmt.Printf("\n⚠️【aiutoCR】%s\n", result.Message)
+	}
+	if failed := printSideEffectSummary(result.SideEffects); failed > 0 {
+		fmt.Printf("\n❌【aiutoCR】%d个必需通知发送失败，终止流程\n", failed)
+		os.Exit(1)
 	}
 
 	fmt.Printf("\n✅【aiutoCR】所有评审完成，无阻断级问题，评审通过 ✅）\n")
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// defaultModel 未指定--model时使用的模型
+const defaultModel = "qwen3-coder-plus"
+
+// 模型调用的采样温度
+const (
+	reviewTemperature = 0.2 // 评审
+	verifyTemperature = 0.0 // 复核，要求结论稳定
+)
+
+// dashScopeEndpoint 百炼原生API地址
+const dashScopeEndpoint = "https://dashscope.aliyuncs.com/api/v1/services/aigc/text-generation/generation"
+
+// callModel 调用百炼原生API，返回模型输出内容；model为空时使用默认模型
+func callModel(config Config, model, prompt string, temperature float64) (string, error) {
+	// 使用配置的模型名称，如果没有指定则使用默认值
+	modelName := defaultModel
+	if model != "" {
+		modelName = model
+	}
+
+	requestBody := map[string]interface{}{
+		"model": modelName,
+		"input": map[string]interface{}{
+			"messages": []map[string]interface{}{
+				{
+					"role":    "user",
+					"content": prompt,
+				},
+			},
+		},
+		"parameters": map[string]interface{}{
+			"max_new_tokens": 9999,
+			"temperature":    temperature,
+			"top_p":          0.9,
+		},
+	}
+
+	requestBodyJSON, err := json.MarshalIndent(requestBody, "", "  ")
+	if err != nil {
+		logDebug("❌【callModel】构造请求体JSON失败：%v\n", err)
+		return "", fmt.Errorf("构造请求体JSON失败：%w", err)
+	}
+	logDebug("ℹ️【callModel】构造的请求体：\n%s\n", string(requestBodyJSON))
+
+	logDebug("ℹ️【callModel】开始调用百炼原生API，模型：%s\n", modelName)
+	resp, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Authorization", fmt.Sprintf("Bearer %s", config.BaichuanAPIKey)).
+		SetBody(requestBody).
+		Post(dashScopeEndpoint)
+
+	if err != nil {
+		logDebug("❌【callModel】百炼API调用失败：%v\n", err)
+		return "", fmt.Errorf("百炼API调用失败：%w", err)
+	}
+
+	logDebug("ℹ️【callModel】百炼API响应状态码：%d\n", resp.StatusCode())
+	logDebug("ℹ️【callModel】百炼API响应内容：%s\n", string(resp.Body()))
+
+	var aiResp struct {
+		Output struct {
+			Choices []struct {
+				Message struct {
+					Content string `json:"content"`
+					Role    string `json:"role"`
+				} `json:"message"`
+				FinishReason string `json:"finish_reason"`
+			} `json:"choices"`
+		} `json:"output"`
+		Usage struct {
+			TotalTokens  int `json:"total_tokens"`
+			OutputTokens int `json:"output_tokens"`
+			InputTokens  int `json:"input_tokens"`
+		} `json:"usage"`
+		RequestID string `json:"request_id"`
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+	}
+	if err := json.Unmarshal(resp.Body(), &aiResp); err != nil {
+		logDebug("❌【callModel】解析百炼API响应失败：%v，响应内容：%s\n", err, string(resp.Body()))
+		return "", fmt.Errorf("解析百炼API响应失败：%w，响应内容：%s", err, string(resp.Body()))
+	}
+
+	if aiResp.Code != "" {
+		logDebug("❌【callModel】百炼API返回业务错误：code=%s, message=%s\n", aiResp.Code, aiResp.Message)
+		return "", fmt.Errorf("百炼API业务错误：%s - %s", aiResp.Code, aiResp.Message)
+	}
+
+	var content string
+	if len(aiResp.Output.Choices) > 0 {
+		content = strings.TrimSpace(aiResp.Output.Choices[0].Message.Content)
+	}
+	logDebug("✅【callModel】百炼API调用成功，RequestID：%s\n", aiResp.RequestID)
+	logDebug("ℹ️【callModel】Token使用情况：Total=%d, Input=%d, Output=%d\n",
+		aiResp.Usage.TotalTokens, aiResp.Usage.InputTokens, aiResp.Usage.OutputTokens)
+	return content, nil
+}
@@ -0,0 +1,661 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Notifier 评审结果通知接口，每种通知渠道一个实现
+type Notifier interface {
+	// Name 通知渠道名称，用于日志和--notify筛选
+	Name() string
+	// Notify 发送评审结果通知
+	Notify(result ReviewResult) error
+}
+
+// NotifierConfig 通知渠道配置（配置文件notifiers数组元素）
+type NotifierConfig struct {
+	Type    string `json:"type"`    // 渠道类型：dingtalk/wecom/feishu/slack/email/webhook
+	Name    string `json:"name"`    // 渠道名称（可选，默认同type，同类型多个渠道时用于区分）
+	Webhook string `json:"webhook"` // Webhook地址（wecom/feishu/slack/webhook）
+	Token   string `json:"token"`   // 钉钉机器人Token
+	Secret  string `json:"secret"`  // 钉钉/飞书机器人加签Secret
+	// Required 是否为必需通知，重试后仍失败时以非0退出
+	Required bool `json:"required"`
+
+	SMTPHost string   `json:"smtp_host"` // SMTP服务器地址（email）
+	SMTPPort int      `json:"smtp_port"` // SMTP端口，默认25；465端口使用SMTPS（隐式TLS）
+	Username string   `json:"username"`  // SMTP用户名
+	Password string   `json:"password"`  // SMTP密码
+	From     string   `json:"from"`      // 发件人
+	To       []string `json:"to"`        // 收件人列表
+
+	Method       string            `json:"method"`        // HTTP方法（webhook），默认POST
+	Headers      map[string]string `json:"headers"`       // 自定义请求头（webhook）
+	Template     string            `json:"template"`      // 请求体模板（webhook，text/template语法，数据为ReviewResult）
+	TemplateFile string            `json:"template_file"` // 请求体模板文件，与template二选一
+}
+
+// NotifyTarget 已启用的通知渠道及其投递要求
+type NotifyTarget struct {
+	Notifier
+	Required bool // 是否为必需通知
+}
+
+// buildNotifiers 根据命令行参数和配置文件构建通知渠道列表，--notify可筛选渠道，筛选的渠道未配置时返回错误
+func buildNotifiers(config Config, renderer *TemplateRenderer) ([]NotifyTarget, error) {
+	var notifiers []NotifyTarget
+	content := &notifyContent{config: config, renderer: renderer}
+
+	// 兼容原有的钉钉命令行参数
+	if config.EnableDingTalk {
+		notifiers = append(notifiers, NotifyTarget{Notifier: &DingTalkNotifier{
+			name:         "dingtalk",
+			token:        config.DingTalkToken,
+			secret:       config.DingTalkSecret,
+			content:      content,
+			atConf:       config.File.DingTalk,
+			targetBranch: config.TargetBranch,
+		}, Required: config.FailOnNotifyError})
+	}
+
+	for _, nc := range config.File.Notifiers {
+		notifier, err := newNotifier(nc, config, content)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, NotifyTarget{Notifier: notifier, Required: nc.Required || config.FailOnNotifyError})
+	}
+
+	if config.Notify == "" {
+		return notifiers, nil
+	}
+	configured := make(map[string]bool)
+	var names []string
+	for _, notifier := range notifiers {
+		configured[notifier.Name()] = true
+		names = append(names, notifier.Name())
+	}
+	selected := make(map[string]bool)
+	var unknown []string
+	for _, name := range strings.Split(config.Notify, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		selected[name] = true
+		if !configured[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		if len(names) == 0 {
+			names = []string{"无"}
+		}
+		return nil, fmt.Errorf("--notify中的通知渠道未配置：%s（已配置：%s）", strings.Join(unknown, ", "), strings.Join(names, ", "))
+	}
+	var filtered []NotifyTarget
+	for _, notifier := range notifiers {
+		if selected[notifier.Name()] {
+			filtered = append(filtered, notifier)
+		}
+	}
+	return filtered, nil
+}
+
+// newNotifier 根据渠道配置创建通知实现
+func newNotifier(nc NotifierConfig, config Config, content *notifyContent) (Notifier, error) {
+	name := nc.Name
+	if name == "" {
+		name = nc.Type
+	}
+
+	switch strings.ToLower(nc.Type) {
+	case "dingtalk":
+		if nc.Token == "" {
+			return nil, fmt.Errorf("通知渠道%s缺少token", name)
+		}
+		return &DingTalkNotifier{
+			name:         name,
+			token:        nc.Token,
+			secret:       nc.Secret,
+			content:      content,
+			atConf:       config.File.DingTalk,
+			targetBranch: config.TargetBranch,
+		}, nil
+	case "wecom":
+		if nc.Webhook == "" {
+			return nil, fmt.Errorf("通知渠道%s缺少webhook", name)
+		}
+		return &WeComNotifier{name: name, webhook: nc.Webhook, content: content}, nil
+	case "feishu", "lark":
+		if nc.Webhook == "" {
+			return nil, fmt.Errorf("通知渠道%s缺少webhook", name)
+		}
+		return &FeishuNotifier{name: name, webhook: nc.Webhook, secret: nc.Secret, content: content}, nil
+	case "slack":
+		if nc.Webhook == "" {
+			return nil, fmt.Errorf("通知渠道%s缺少webhook", name)
+		}
+		return &SlackNotifier{name: name, webhook: nc.Webhook, content: content}, nil
+	case "email":
+		if nc.SMTPHost == "" || nc.From == "" || len(nc.To) == 0 {
+			return nil, fmt.Errorf("通知渠道%s缺少smtp_host/from/to", name)
+		}
+		port := nc.SMTPPort
+		if port == 0 {
+			port = 25
+		}
+		return &EmailNotifier{
+			name:     name,
+			addr:     fmt.Sprintf("%s:%d", nc.SMTPHost, port),
+			host:     nc.SMTPHost,
+			username: nc.Username,
+			password: nc.Password,
+			from:     nc.From,
+			to:       nc.To,
+			content:  content,
+		}, nil
+	case "webhook":
+		if nc.Webhook == "" {
+			return nil, fmt.Errorf("通知渠道%s缺少webhook", name)
+		}
+		tmplText := nc.Template
+		if nc.TemplateFile != "" {
+			data, err := os.ReadFile(nc.TemplateFile)
+			if err != nil {
+				return nil, fmt.Errorf("读取通知渠道%s的模板文件失败：%w", name, err)
+			}
+			tmplText = string(data)
+		}
+		var tmpl *template.Template
+		if tmplText != "" {
+			var err error
+			tmpl, err = template.New(name).Funcs(templateFuncs).Parse(tmplText)
+			if err != nil {
+				return nil, fmt.Errorf("解析通知渠道%s的模板失败：%w", name, err)
+			}
+		}
+		method := strings.ToUpper(nc.Method)
+		if method == "" {
+			method = "POST"
+		}
+		return &WebhookNotifier{name: name, url: nc.Webhook, method: method, headers: nc.Headers, tmpl: tmpl}, nil
+	default:
+		return nil, fmt.Errorf("不支持的通知渠道类型：%s", nc.Type)
+	}
+}
+
+// notifyAll 依次调用所有通知渠道（失败自动重试），单个渠道失败不影响其他渠道
+func notifyAll(notifiers []NotifyTarget, result ReviewResult, retries int) []SideEffect {
+	var effects []SideEffect
+	for _, notifier := range notifiers {
+		attempts, err := withRetry(retries, func() error {
+			return notifier.Notify(result)
+		})
+		if err != nil {
+			fmt.Printf("⚠️【aiutoCR】%s通知发送失败：%v\n", notifier.Name(), err)
+		} else {
+			logDebug("✅【notifyAll】%s通知发送成功\n", notifier.Name())
+		}
+		effects = append(effects, newSideEffect(EffectNotification, notifier.Name(), notifier.Required, attempts, err))
+	}
+	return effects
+}
+
+// toJSON 模板函数：将任意值序列化为JSON字符串（字符串值会带引号并转义）
+func toJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// notifyContent 各通知渠道共用的标题和正文渲染
+type notifyContent struct {
+	config   Config
+	renderer *TemplateRenderer
+}
+
+// title 通知标题
+func (c *notifyContent) title() string {
+	return tr(c.renderer.Locale(), "title")
+}
+
+// markdown 渲染通知正文（notify模板），问题数量受--max-issues限制
+func (c *notifyContent) markdown(result ReviewResult) (string, error) {
+	data := c.renderer.NewData(c.config, result, nil, "", c.config.MaxIssues)
+	return c.renderer.Render(TemplateNotify, data)
+}
+
+// postWebhookJSON 以JSON格式POST到Webhook，并检查HTTP状态码
+func postWebhookJSON(url string, body interface{}, result interface{}) error {
+	req := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(body)
+	if result != nil {
+		req.SetResult(result)
+	}
+	resp, err := req.Post(url)
+	if err != nil {
+		return fmt.Errorf("Webhook请求失败：%w", err)
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return fmt.Errorf("Webhook返回异常状态码：%d，响应内容：%s", resp.StatusCode(), string(resp.Body()))
+	}
+	return nil
+}
+
+// DingTalkNotifier 钉钉机器人通知
+type DingTalkNotifier struct {
+	name         string
+	token        string
+	secret       string
+	content      *notifyContent
+	atConf       DingTalkConfig
+	targetBranch string
+}
+
+func (d *DingTalkNotifier) Name() string {
+	return d.name
+}
+
+func (d *DingTalkNotifier) Notify(result ReviewResult) error {
+	markdown, err := d.content.markdown(result)
+	if err != nil {
+		return err
+	}
+
+	// 钉钉Markdown消息需要在正文中包含@手机号/@userId，被@的人才会收到提醒
+	at := resolveDingTalkAt(d.atConf, result, d.targetBranch)
+	var mentions []string
+	for _, mobile := range at.AtMobiles {
+		mentions = append(mentions, "@"+mobile)
+	}
+	for _, userID := range at.AtUserIds {
+		mentions = append(mentions, "@"+userID)
+	}
+	if len(mentions) > 0 {
+		markdown += strings.Join(mentions, " ") + "\n"
+	}
+	logDebug("ℹ️【DingTalkNotifier】@所有人：%v，@手机号：%v，@userId：%v\n", at.IsAtAll, at.AtMobiles, at.AtUserIds)
+
+	return sendDingTalkMarkdown(d.token, d.secret, d.content.title(), markdown, at)
+}
+
+// dingTalkAt 钉钉消息的@对象
+type dingTalkAt struct {
+	AtMobiles []string `json:"atMobiles,omitempty"`
+	AtUserIds []string `json:"atUserIds,omitempty"`
+	IsAtAll   bool     `json:"isAtAll"`
+}
+
+// resolveDingTalkAt 根据评审结果计算需要@的人：默认@MR作者和评审人，
+// 仅当受保护分支上存在block级问题时才@所有人。目标分支以--target-branch为准，未指定时取MR详情中的目标分支
+func resolveDingTalkAt(conf DingTalkConfig, result ReviewResult, targetBranch string) dingTalkAt {
+	var at dingTalkAt
+
+	if targetBranch == "" && result.MRInfo != nil {
+		targetBranch = result.MRInfo.TargetBranch
+	}
+	if result.Status == "blocked" && isProtectedBranch(conf.ProtectedBranches, targetBranch) {
+		for _, issue := range result.BlockIssues {
+			if issue.Level == LevelBlock {
+				at.IsAtAll = true
+				return at
+			}
+		}
+	}
+
+	// 收集候选人：MR作者 > 提交人，再加上MR评审人和配置的固定评审人
+	var candidates []string
+	if result.MRInfo != nil {
+		if result.MRInfo.Author != nil {
+			candidates = append(candidates, result.MRInfo.Author.Email, result.MRInfo.Author.Name)
+		}
+		for _, reviewer := range result.MRInfo.Reviewers {
+			candidates = append(candidates, reviewer.Email, reviewer.Name)
+		}
+	}
+	if result.CommitInfo != nil {
+		candidates = append(candidates, result.CommitInfo.AuthorEmail, result.CommitInfo.AuthorName)
+	}
+	candidates = append(candidates, conf.Reviewers...)
+
+	seen := make(map[string]bool)
+	for _, candidate := range candidates {
+		user, ok := lookupDingTalkUser(conf.Users, candidate)
+		if !ok {
+			continue
+		}
+		if user.Mobile != "" && !seen[user.Mobile] {
+			seen[user.Mobile] = true
+			at.AtMobiles = append(at.AtMobiles, user.Mobile)
+		} else if user.Mobile == "" && user.UserID != "" && !seen[user.UserID] {
+			seen[user.UserID] = true
+			at.AtUserIds = append(at.AtUserIds, user.UserID)
+		}
+	}
+	return at
+}
+
+// lookupDingTalkUser 按邮箱/姓名查找钉钉账号（忽略大小写）
+func lookupDingTalkUser(users map[string]DingTalkUser, key string) (DingTalkUser, bool) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return DingTalkUser{}, false
+	}
+	if user, ok := users[key]; ok {
+		return user, true
+	}
+	for k, user := range users {
+		if strings.EqualFold(k, key) {
+			return user, true
+		}
+	}
+	return DingTalkUser{}, false
+}
+
+// isProtectedBranch 判断分支是否为受保护分支，支持通配符（如release/*）
+func isProtectedBranch(patterns []string, branch string) bool {
+	if branch == "" {
+		return false
+	}
+	for _, pattern := range patterns {
+		if pattern == branch {
+			return true
+		}
+		if matched, _ := path.Match(pattern, branch); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// sendDingTalkMarkdown 调用钉钉机器人Webhook发送Markdown消息（加签模式）。
+// 不使用github.com/blinkbean/dingtalk：其v1.1.3的@对象只有atMobiles和isAtAll，无法按users配置中的user_id@人，
+// 且只能通过不可导出的选项类型构造@对象
+func sendDingTalkMarkdown(token, secret, title, text string, at dingTalkAt) error {
+	params := map[string]string{"access_token": token}
+	if secret != "" {
+		timestamp := time.Now().UnixNano() / 1e6
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(fmt.Sprintf("%d\n%s", timestamp, secret)))
+		params["timestamp"] = fmt.Sprintf("%d", timestamp)
+		params["sign"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	var dingResp struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	resp, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetQueryParams(params).
+		SetBody(map[string]interface{}{
+			"msgtype": "markdown",
+			"markdown": map[string]string{
+				"title": title,
+				"text":  text,
+			},
+			"at": at,
+		}).
+		SetResult(&dingResp).
+		Post("https://oapi.dingtalk.com/robot/send")
+	if err != nil {
+		return fmt.Errorf("钉钉机器人请求失败：%w", err)
+	}
+	if resp.StatusCode() != 200 {
+		return fmt.Errorf("钉钉机器人返回异常状态码：%d，响应内容：%s", resp.StatusCode(), string(resp.Body()))
+	}
+	if dingResp.ErrCode != 0 {
+		return fmt.Errorf("钉钉机器人返回错误：%d - %s", dingResp.ErrCode, dingResp.ErrMsg)
+	}
+	return nil
+}
+
+// WeComNotifier 企业微信群机器人通知
+type WeComNotifier struct {
+	name    string
+	webhook string
+	content *notifyContent
+}
+
+func (w *WeComNotifier) Name() string {
+	return w.name
+}
+
+func (w *WeComNotifier) Notify(result ReviewResult) error {
+	markdown, err := w.content.markdown(result)
+	if err != nil {
+		return err
+	}
+	var wecomResp struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	err = postWebhookJSON(w.webhook, map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"content": markdown,
+		},
+	}, &wecomResp)
+	if err != nil {
+		return err
+	}
+	if wecomResp.ErrCode != 0 {
+		return fmt.Errorf("企业微信机器人返回错误：%d - %s", wecomResp.ErrCode, wecomResp.ErrMsg)
+	}
+	return nil
+}
+
+// FeishuNotifier 飞书/Lark群机器人通知（消息卡片）
+type FeishuNotifier struct {
+	name    string
+	webhook string
+	secret  string
+	content *notifyContent
+}
+
+func (f *FeishuNotifier) Name() string {
+	return f.name
+}
+
+func (f *FeishuNotifier) Notify(result ReviewResult) error {
+	markdown, err := f.content.markdown(result)
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"msg_type": "interactive",
+		"card": map[string]interface{}{
+			"header": map[string]interface{}{
+				"title": map[string]string{"tag": "plain_text", "content": f.content.title()},
+			},
+			"elements": []map[string]string{
+				{"tag": "markdown", "content": markdown},
+			},
+		},
+	}
+	// 飞书加签：以"timestamp\nsecret"为密钥对空串做HmacSHA256
+	if f.secret != "" {
+		timestamp := time.Now().Unix()
+		mac := hmac.New(sha256.New, []byte(fmt.Sprintf("%d\n%s", timestamp, f.secret)))
+		body["timestamp"] = fmt.Sprintf("%d", timestamp)
+		body["sign"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	var feishuResp struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := postWebhookJSON(f.webhook, body, &feishuResp); err != nil {
+		return err
+	}
+	if feishuResp.Code != 0 {
+		return fmt.Errorf("飞书机器人返回错误：%d - %s", feishuResp.Code, feishuResp.Msg)
+	}
+	return nil
+}
+
+// SlackNotifier Slack Incoming Webhook通知
+type SlackNotifier struct {
+	name    string
+	webhook string
+	content *notifyContent
+}
+
+func (s *SlackNotifier) Name() string {
+	return s.name
+}
+
+func (s *SlackNotifier) Notify(result ReviewResult) error {
+	markdown, err := s.content.markdown(result)
+	if err != nil {
+		return err
+	}
+	return postWebhookJSON(s.webhook, map[string]interface{}{
+		"text": markdown,
+	}, nil)
+}
+
+// EmailNotifier SMTP邮件通知
+type EmailNotifier struct {
+	name     string
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	to       []string
+	content  *notifyContent
+}
+
+func (e *EmailNotifier) Name() string {
+	return e.name
+}
+
+func (e *EmailNotifier) Notify(result ReviewResult) error {
+	markdown, err := e.content.markdown(result)
+	if err != nil {
+		return err
+	}
+	subject := e.content.title()
+	if result.Status == "blocked" {
+		subject = "❌ " + subject
+	}
+
+	var msg bytes.Buffer
+	msg.WriteString(fmt.Sprintf("From: %s\r\n", e.from))
+	msg.WriteString(fmt.Sprintf("To: %s\r\n", strings.Join(e.to, ", ")))
+	msg.WriteString(fmt.Sprintf("Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject)))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(markdown)
+
+	var auth smtp.Auth
+	if e.username != "" {
+		auth = smtp.PlainAuth("", e.username, e.password, e.host)
+	}
+	send := smtp.SendMail
+	if strings.HasSuffix(e.addr, ":465") {
+		send = sendMailTLS
+	}
+	if err := send(e.addr, auth, e.from, e.to, msg.Bytes()); err != nil {
+		return fmt.Errorf("SMTP发送邮件失败：%w", err)
+	}
+	return nil
+}
+
+// sendMailTLS 通过SMTPS（465端口，连接建立时即为TLS）发送邮件，smtp.SendMail只支持明文连接后STARTTLS
+func sendMailTLS(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	host := addr[:strings.LastIndex(addr, ":")]
+	conn, err := tls.Dial("tcp", addr, &tls.Config{ServerName: host})
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if auth != nil {
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := c.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// WebhookNotifier 通用JSON Webhook通知，请求体可通过模板自定义
+type WebhookNotifier struct {
+	name    string
+	url     string
+	method  string
+	headers map[string]string
+	tmpl    *template.Template
+}
+
+func (w *WebhookNotifier) Name() string {
+	return w.name
+}
+
+func (w *WebhookNotifier) Notify(result ReviewResult) error {
+	var payload []byte
+	if w.tmpl != nil {
+		var buf bytes.Buffer
+		if err := w.tmpl.Execute(&buf, result); err != nil {
+			return fmt.Errorf("渲染Webhook模板失败：%w", err)
+		}
+		payload = buf.Bytes()
+	} else {
+		var err error
+		if payload, err = json.Marshal(result); err != nil {
+			return fmt.Errorf("序列化评审结果失败：%w", err)
+		}
+	}
+
+	resp, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetHeaders(w.headers).
+		SetBody(payload).
+		Execute(w.method, w.url)
+	if err != nil {
+		return fmt.Errorf("Webhook请求失败：%w", err)
+	}
+	if resp.StatusCode() < 200 || resp.StatusCode() >= 300 {
+		return fmt.Errorf("Webhook返回异常状态码：%d，响应内容：%s", resp.StatusCode(), string(resp.Body()))
+	}
+	return nil
+}
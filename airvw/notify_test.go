@@ -0,0 +1,68 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestResolveDingTalkAt 测试@对象的计算：默认@MR作者和评审人，受保护分支上有block级问题时@所有人，
+// 目标分支以--target-branch为准，未指定时取MR详情
+func TestResolveDingTalkAt(t *testing.T) {
+	conf := DingTalkConfig{
+		Users: map[string]DingTalkUser{
+			"zhangsan@example.com": {Mobile: "13800000001"},
+			"李四":                   {UserID: "manager1234"},
+			"wangwu@example.com":   {Mobile: "13800000003"},
+		},
+		Reviewers:         []string{"wangwu@example.com", "ZhangSan@example.com"},
+		ProtectedBranches: []string{"master", "release/*"},
+	}
+	mr := &MRInfo{
+		Author:       &MRUser{Name: "张三", Email: "zhangsan@example.com"},
+		Reviewers:    []MRUser{{Name: "李四"}},
+		TargetBranch: "feature/login",
+	}
+	blocked := []BlockIssue{{Level: LevelBlock, File: "main.go", Line: "1", Issue: "SQL注入"}}
+	people := dingTalkAt{AtMobiles: []string{"13800000001", "13800000003"}, AtUserIds: []string{"manager1234"}}
+
+	tests := []struct {
+		name         string
+		result       ReviewResult
+		targetBranch string
+		want         dingTalkAt
+	}{
+		{"通过时@作者和评审人", ReviewResult{Status: "passed", MRInfo: mr}, "", people},
+		{"非受保护分支的block问题不@所有人", ReviewResult{Status: "blocked", MRInfo: mr, BlockIssues: blocked}, "", people},
+		{"命令行指定的受保护分支优先于MR详情", ReviewResult{Status: "blocked", MRInfo: mr, BlockIssues: blocked}, "release/1.0", dingTalkAt{IsAtAll: true}},
+		{"命令行指定的非受保护分支优先于MR详情", ReviewResult{Status: "blocked", MRInfo: &MRInfo{Author: mr.Author, Reviewers: mr.Reviewers, TargetBranch: "master"}, BlockIssues: blocked}, "feature/login", people},
+		{"未指定时取MR详情的目标分支", ReviewResult{Status: "blocked", MRInfo: &MRInfo{Author: mr.Author, Reviewers: mr.Reviewers, TargetBranch: "master"}, BlockIssues: blocked}, "", dingTalkAt{IsAtAll: true}},
+		{"受保护分支上只有high问题不@所有人", ReviewResult{Status: "blocked", MRInfo: mr, BlockIssues: []BlockIssue{{Level: LevelHigh}}}, "master", people},
+		{"Commit评审@提交人", ReviewResult{Status: "passed", CommitInfo: &CommitInfo{AuthorName: "李四"}}, "", dingTalkAt{AtMobiles: []string{"13800000003", "13800000001"}, AtUserIds: []string{"manager1234"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resolveDingTalkAt(conf, tt.result, tt.targetBranch); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolveDingTalkAt() = %+v，期望%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBuildNotifiersUnknownName 测试--notify按名称筛选渠道，名称未配置时返回错误
+func TestBuildNotifiersUnknownName(t *testing.T) {
+	config := Config{EnableDingTalk: true, DingTalkToken: "token"}
+	config.File.Notifiers = []NotifierConfig{{Type: "wecom", Webhook: "https://example.com/hook"}}
+	renderer := newTemplateRenderer(LocaleZH, TemplateConfig{})
+
+	config.Notify = "wecom"
+	notifiers, err := buildNotifiers(config, renderer)
+	if err != nil || len(notifiers) != 1 || notifiers[0].Name() != "wecom" {
+		t.Errorf("buildNotifiers(wecom) = %v, %v", notifiers, err)
+	}
+
+	config.Notify = "dingtalk,slak"
+	if _, err := buildNotifiers(config, renderer); err == nil || !strings.Contains(err.Error(), "slak") || !strings.Contains(err.Error(), "dingtalk, wecom") {
+		t.Errorf("buildNotifiers(dingtalk,slak)错误 = %v，期望列出未配置的slak和已配置的渠道", err)
+	}
+}
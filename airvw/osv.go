@@ -0,0 +1,356 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// osvEntry OSV格式的漏洞记录，只包含用到的字段（https://ossf.github.io/osv-schema/）
+type osvEntry struct {
+	ID               string        `json:"id"`
+	Aliases          []string      `json:"aliases"`
+	Summary          string        `json:"summary"`
+	Withdrawn        string        `json:"withdrawn"`
+	Affected         []osvAffected `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string   `json:"severity"` // GitHub Advisory的严重程度：CRITICAL/HIGH/MODERATE/LOW
+		CWEIDs   []string `json:"cwe_ids"`
+	} `json:"database_specific"`
+}
+
+// osvAffected 受影响的包及版本范围
+type osvAffected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges []struct {
+		Type   string `json:"type"`
+		Events []struct {
+			Introduced   string `json:"introduced"`
+			Fixed        string `json:"fixed"`
+			LastAffected string `json:"last_affected"`
+		} `json:"events"`
+	} `json:"ranges"`
+	Versions []string `json:"versions"`
+}
+
+// Vulnerability 依赖版本命中的漏洞
+type Vulnerability struct {
+	ID       string   // OSV编号，如GHSA-xxxx、GO-2023-0001
+	Aliases  []string // 别名，如CVE编号
+	Summary  string   // 漏洞概述
+	Severity string   // 严重程度，未知时为空
+	CWE      string   // 首个CWE编号
+	Fixed    string   // 修复版本，未知时为空
+}
+
+// OSVDatabase 离线漏洞库，按生态和包名索引
+type OSVDatabase struct {
+	entries map[string][]*osvEntry
+}
+
+// pypiNameSeparator PyPI包名中等价的分隔符（PEP 503）
+var pypiNameSeparator = regexp.MustCompile(`[-_.]+`)
+
+// versionTokenPattern 版本号中的数字段和字母段
+var versionTokenPattern = regexp.MustCompile(`\d+|[A-Za-z]+`)
+
+// loadOSVDatabase 加载离线漏洞库，支持OSV导出的zip包（如https://osv-vulnerabilities.storage.googleapis.com/<生态>/all.zip）、
+// 包含OSV JSON文件的目录，以及单条记录或记录数组的JSON文件
+func loadOSVDatabase(path string) (*OSVDatabase, error) {
+	db := &OSVDatabase{entries: make(map[string][]*osvEntry)}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取漏洞库%s失败：%w", path, err)
+	}
+
+	switch {
+	case info.IsDir():
+		err = filepath.Walk(path, func(file string, fi os.FileInfo, err error) error {
+			if err != nil || fi.IsDir() || !strings.HasSuffix(fi.Name(), ".json") {
+				return err
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			return db.add(file, data)
+		})
+	case strings.HasSuffix(strings.ToLower(path), ".zip"):
+		err = db.addZip(path)
+	default:
+		var data []byte
+		if data, err = os.ReadFile(path); err == nil {
+			err = db.add(path, data)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("加载漏洞库%s失败：%w", path, err)
+	}
+	logDebug("✅【loadOSVDatabase】已加载漏洞库%s：%d个包\n", path, len(db.entries))
+	return db, nil
+}
+
+// addZip 加载OSV导出的zip包
+func (db *OSVDatabase) addZip(path string) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	for _, f := range archive.File {
+		if !strings.HasSuffix(f.Name, ".json") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		if err := db.add(f.Name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// add 加载单个JSON文件中的漏洞记录，格式不符的记录跳过
+func (db *OSVDatabase) add(name string, data []byte) error {
+	var entries []*osvEntry
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			logDebug("⚠️【OSVDatabase】跳过无法解析的漏洞记录%s：%v\n", name, err)
+			return nil
+		}
+	} else {
+		var entry osvEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			logDebug("⚠️【OSVDatabase】跳过无法解析的漏洞记录%s：%v\n", name, err)
+			return nil
+		}
+		entries = []*osvEntry{&entry}
+	}
+	for _, entry := range entries {
+		if entry.Withdrawn != "" {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, affected := range entry.Affected {
+			key := osvKey(affected.Package.Ecosystem, affected.Package.Name)
+			if !seen[key] {
+				seen[key] = true
+				db.entries[key] = append(db.entries[key], entry)
+			}
+		}
+	}
+	return nil
+}
+
+// Lookup 查询依赖版本命中的漏洞
+func (db *OSVDatabase) Lookup(ecosystem, name, version string) []Vulnerability {
+	if db == nil || version == "" {
+		return nil
+	}
+	key := osvKey(ecosystem, name)
+	var vulns []Vulnerability
+	for _, entry := range db.entries[key] {
+		for _, affected := range entry.Affected {
+			if osvKey(affected.Package.Ecosystem, affected.Package.Name) != key {
+				continue
+			}
+			hit, fixed := affected.affects(version)
+			if !hit {
+				continue
+			}
+			vuln := Vulnerability{ID: entry.ID, Aliases: entry.Aliases, Summary: entry.Summary, Severity: strings.ToUpper(entry.DatabaseSpecific.Severity), Fixed: fixed}
+			if len(entry.DatabaseSpecific.CWEIDs) > 0 {
+				vuln.CWE = entry.DatabaseSpecific.CWEIDs[0]
+			}
+			vulns = append(vulns, vuln)
+			break
+		}
+	}
+	return vulns
+}
+
+// affects 判断版本是否在受影响范围内，命中时返回该范围的修复版本。
+// 只处理SEMVER和ECOSYSTEM类型的范围，ECOSYSTEM版本按通用规则比较（见compareVersions）
+func (a osvAffected) affects(version string) (bool, string) {
+	for _, r := range a.Ranges {
+		if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+			continue
+		}
+		introduced, open := "", false
+		for _, e := range r.Events {
+			switch {
+			case e.Introduced != "":
+				introduced, open = e.Introduced, true
+			case e.Fixed != "":
+				if open && versionAtLeast(version, introduced) && compareVersions(version, e.Fixed) < 0 {
+					return true, e.Fixed
+				}
+				open = false
+			case e.LastAffected != "":
+				if open && versionAtLeast(version, introduced) && compareVersions(version, e.LastAffected) <= 0 {
+					return true, ""
+				}
+				open = false
+			}
+		}
+		if open && versionAtLeast(version, introduced) {
+			return true, ""
+		}
+	}
+	for _, v := range a.Versions {
+		if compareVersions(v, version) == 0 {
+			return true, ""
+		}
+	}
+	return false, ""
+}
+
+// versionAtLeast 版本不低于introduced，「0」表示从最早的版本开始
+func versionAtLeast(version, introduced string) bool {
+	return introduced == "0" || compareVersions(version, introduced) >= 0
+}
+
+// osvKey 漏洞库索引键，PyPI包名按PEP 503归一化
+func osvKey(ecosystem, name string) string {
+	if ecosystem == "PyPI" {
+		name = pypiNameSeparator.ReplaceAllString(strings.ToLower(name), "-")
+	}
+	return ecosystem + "|" + name
+}
+
+// compareVersions 比较两个版本号，兼容semver、Go伪版本、PyPI和Maven的常见写法：
+// 忽略前缀v和+之后的构建信息；-之前的正式版本部分按数字段和字母段依次比较，数字段按数值比较，
+// 一方已结束时，另一方后续为数字段则更大，为字母段（如PyPI的1.0rc1）则视为预发布版本更小；
+// -之后为预发布部分（如rc.1、SNAPSHOT、Go伪版本的0.20200101000000-abcdef），按SemVer规则低于对应的正式版本
+func compareVersions(a, b string) int {
+	releaseA, preA := splitPrerelease(a)
+	releaseB, preB := splitPrerelease(b)
+	if c := compareRelease(versionTokens(releaseA), versionTokens(releaseB)); c != 0 {
+		return c
+	}
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	}
+	return comparePrerelease(preA, preB)
+}
+
+// splitPrerelease 去掉前缀v和构建信息后，拆分为正式版本部分和预发布部分
+func splitPrerelease(version string) (string, string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexByte(version, '+'); i >= 0 {
+		version = version[:i]
+	}
+	if i := strings.IndexByte(version, '-'); i >= 0 {
+		return version[:i], version[i+1:]
+	}
+	return version, ""
+}
+
+// compareRelease 按数字段和字母段依次比较正式版本部分
+func compareRelease(ta, tb []string) int {
+	for i := 0; i < len(ta) || i < len(tb); i++ {
+		switch {
+		case i >= len(ta):
+			if isDigits(tb[i]) {
+				return -1
+			}
+			return 1
+		case i >= len(tb):
+			if isDigits(ta[i]) {
+				return 1
+			}
+			return -1
+		}
+		// 正式版本部分的字母段（如rc、beta）表示预发布，低于同位置的数字段
+		if xNum, yNum := isDigits(ta[i]), isDigits(tb[i]); xNum != yNum {
+			if xNum {
+				return 1
+			}
+			return -1
+		}
+		if c := compareIdentifier(ta[i], tb[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// comparePrerelease 按SemVer规则比较预发布部分：以.分隔的标识依次比较，全部相同时标识少的更小
+func comparePrerelease(a, b string) int {
+	ia, ib := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(ia) && i < len(ib); i++ {
+		if c := compareIdentifier(ia[i], ib[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(ia) < len(ib):
+		return -1
+	case len(ia) > len(ib):
+		return 1
+	}
+	return 0
+}
+
+// compareIdentifier 比较单个版本标识：都是数字时按数值比较，数字低于非数字，非数字忽略大小写按字典序比较
+func compareIdentifier(x, y string) int {
+	xNum, yNum := isDigits(x), isDigits(y)
+	switch {
+	case xNum && yNum:
+		x, y = strings.TrimLeft(x, "0"), strings.TrimLeft(y, "0")
+		if len(x) != len(y) {
+			if len(x) < len(y) {
+				return -1
+			}
+			return 1
+		}
+	case xNum:
+		return -1
+	case yNum:
+		return 1
+	default:
+		x, y = strings.ToLower(x), strings.ToLower(y)
+	}
+	return strings.Compare(x, y)
+}
+
+// versionTokens 拆分版本号为数字段和字母段
+func versionTokens(version string) []string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexByte(version, '+'); i >= 0 {
+		version = version[:i]
+	}
+	return versionTokenPattern.FindAllString(version, -1)
+}
+
+// isDigits 判断字符串是否全为数字
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
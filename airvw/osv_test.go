@@ -0,0 +1,107 @@
+package main
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestCompareVersions 测试版本号比较：数字段按数值比较，预发布版本（含Go伪版本）按SemVer规则低于正式版本
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.10.0", "1.9.3", 1},
+		{"v1.2.3", "1.2.3", 0},
+		{"2.0.0-rc1", "2.0.0", -1},
+		{"1.0.0.1", "1.0.0", 1},
+		{"0.0.0-20230101120000-abcdef", "0.1.0", -1},
+		{"v2.0.0+incompatible", "2.0.0", 0},
+		{"5.3.20.RELEASE", "5.3.21", -1},
+		{"1.0rc1", "1.0", -1},
+		{"1.0.1", "1.0.rc1", 1},
+		// 预发布版本（含Go伪版本）按SemVer规则低于正式版本
+		{"1.0.0-1", "1.0.0", -1},
+		{"1.0.0-SNAPSHOT", "1.0.0", -1},
+		{"v1.2.3-0.20200101000000-abcdef123456", "v1.2.3", -1},
+		{"v1.2.3-0.20200101000000-abcdef123456", "v1.2.2", 1},
+		{"v1.2.4-0.20200101000000-abcdef123456", "v1.2.3", 1},
+		{"v0.0.0-20220101000000-abcdef123456", "v0.0.0-20220906165146-f3363e06e74c", -1},
+		{"v1.2.3-pre.0.20200101000000-abcdef123456", "v1.2.3-pre", 1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		{"1.0.0-beta.2", "1.0.0-beta.11", -1},
+		{"1.0.0-rc.1", "1.0.0-RC.1", 0},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d，期望%d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// TestOSVLookup 测试从zip包加载漏洞库，按版本范围、last_affected和枚举版本匹配，PyPI包名归一化，已撤回的漏洞不生效
+func TestOSVLookup(t *testing.T) {
+	entries := map[string]string{
+		"GO-2022-0969.json": `{"id": "GO-2022-0969", "aliases": ["CVE-2022-27664"],
+			"affected": [{"package": {"ecosystem": "Go", "name": "golang.org/x/net"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.0.0-20220906165146-f3363e06e74c"}]}]}]}`,
+		"GO-2023-1571.json": `{"id": "GO-2023-1571",
+			"affected": [{"package": {"ecosystem": "Go", "name": "golang.org/x/text"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.3.8"}]}]}]}`,
+		"PYSEC-2023-74.json": `{"id": "PYSEC-2023-74", "database_specific": {"severity": "MODERATE"},
+			"affected": [{"package": {"ecosystem": "PyPI", "name": "Requests"},
+				"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.3.0"}, {"last_affected": "2.30.0"}]}]}]}`,
+		"GHSA-old.json": `{"id": "GHSA-old", "withdrawn": "2023-01-01T00:00:00Z",
+			"affected": [{"package": {"ecosystem": "Maven", "name": "com.alibaba:fastjson"}, "versions": ["1.2.83"]}]}`,
+		"GHSA-fastjson.json": `{"id": "GHSA-fastjson",
+			"affected": [{"package": {"ecosystem": "Maven", "name": "com.alibaba:fastjson"}, "versions": ["1.2.68"]}]}`,
+	}
+	path := filepath.Join(t.TempDir(), "all.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	for name, content := range entries {
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.Write([]byte(content))
+	}
+	w.Close()
+	f.Close()
+
+	db, err := loadOSVDatabase(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		ecosystem, name, version string
+		want                     string // 命中的漏洞编号，为空表示不命中
+		fixed                    string
+	}{
+		{"Go", "golang.org/x/net", "v0.0.0-20220524220425-1d687d428aca", "GO-2022-0969", "0.0.0-20220906165146-f3363e06e74c"},
+		{"Go", "golang.org/x/net", "v0.7.0", "", ""},
+		{"Go", "golang.org/x/text", "v0.3.8-0.20220722155301-d03b41800055", "GO-2023-1571", "0.3.8"},
+		{"Go", "golang.org/x/text", "v0.3.8", "", ""},
+		{"Go", "golang.org/x/text", "v0.3.9-0.20230101000000-abcdef123456", "", ""},
+		{"PyPI", "requests", "2.25.0", "PYSEC-2023-74", ""},
+		{"PyPI", "requests", "2.31.0", "", ""},
+		{"Maven", "com.alibaba:fastjson", "1.2.68", "GHSA-fastjson", ""},
+		{"Maven", "com.alibaba:fastjson", "1.2.83", "", ""},
+	}
+	for _, tt := range tests {
+		vulns := db.Lookup(tt.ecosystem, tt.name, tt.version)
+		got, fixed := "", ""
+		if len(vulns) > 0 {
+			got, fixed = vulns[0].ID, vulns[0].Fixed
+		}
+		if got != tt.want || fixed != tt.fixed || len(vulns) > 1 {
+			t.Errorf("Lookup(%s, %s) = %+v，期望%q（修复版本%q）", tt.name, tt.version, vulns, tt.want, tt.fixed)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 卡点策略模式
+const (
+	PolicyEnforce  = "enforce"  // 违反策略时终止流程（默认）
+	PolicyAdvisory = "advisory" // 仅报告，不终止流程（试运行）
+)
+
+// levelOrder 问题等级从高到低
+var levelOrder = []string{LevelBlock, LevelHigh, LevelMedium, LevelSuggest}
+
+// PolicyConfig 卡点策略配置
+type PolicyConfig struct {
+	Mode          string           `json:"mode"`           // enforce/advisory，默认enforce
+	Thresholds    map[string]int   `json:"thresholds"`     // 各等级允许的最大问题数，超过即阻断，未配置的等级不卡点
+	Overrides     []PolicyOverride `json:"overrides"`      // 按路径/分类覆盖，按顺序匹配第一条
+	MinConfidence float64          `json:"min_confidence"` // block/high级问题参与卡点的最低置信度，低于该值只提示不卡点
+}
+
+// PolicyOverride 按路径/分类覆盖的策略
+type PolicyOverride struct {
+	Paths      []string       `json:"paths"`      // 适用路径（glob），为空表示所有路径
+	Categories []string       `json:"categories"` // 适用的规则ID/分类关键字，为空表示所有问题
+	Thresholds map[string]int `json:"thresholds"` // 该范围内单独计数的阈值，未配置时计入全局阈值
+	Promote    int            `json:"promote"`    // 问题等级提升的级数（如受保护路径的medium提升为high）
+	Exempt     bool           `json:"exempt"`     // 该范围内的问题只报告不卡点
+}
+
+// PolicyDecision 卡点策略的判定结果
+type PolicyDecision struct {
+	Mode       string            `json:"mode"`                 // 策略模式
+	Blocked    bool              `json:"blocked"`              // 是否违反策略（advisory模式下不终止流程）
+	Violations []PolicyViolation `json:"violations,omitempty"` // 超出阈值的明细
+	Issues     []BlockIssue      `json:"-"`                    // 导致阻断的问题
+}
+
+// PolicyViolation 单条超出阈值的记录
+type PolicyViolation struct {
+	Scope string `json:"scope"` // 计数范围：global或覆盖规则的路径
+	Level string `json:"level"` // 问题等级
+	Count int    `json:"count"` // 问题数
+	Max   int    `json:"max"`   // 允许的最大问题数
+}
+
+// Policy 卡点策略
+type Policy struct {
+	mode          string
+	thresholds    map[string]int
+	overrides     []PolicyOverride
+	minConfidence float64
+}
+
+// validateLevel 校验评审等级
+func validateLevel(level string) error {
+	if levelRank(level) < 0 {
+		return fmt.Errorf("评审等级无效：%s（可选：block/high/medium/suggest）", level)
+	}
+	return nil
+}
+
+// thresholdsForLevel 根据--level生成阈值：该等级及以上的问题出现即阻断
+func thresholdsForLevel(level string) (map[string]int, error) {
+	if err := validateLevel(level); err != nil {
+		return nil, err
+	}
+	thresholds := make(map[string]int)
+	for _, l := range levelOrder {
+		thresholds[l] = 0
+		if l == level {
+			break
+		}
+	}
+	return thresholds, nil
+}
+
+// newPolicy 合并命令行与配置文件生成卡点策略：显式指定--level时以命令行为准，否则优先使用配置文件中的阈值
+func newPolicy(config Config, levelExplicit bool) (*Policy, error) {
+	conf := config.File.Policy
+	policy := &Policy{mode: conf.Mode, thresholds: conf.Thresholds, overrides: conf.Overrides, minConfidence: conf.MinConfidence}
+	if config.MinConfidence > 0 {
+		policy.minConfidence = config.MinConfidence
+	}
+	if levelExplicit || len(policy.thresholds) == 0 {
+		thresholds, err := thresholdsForLevel(config.ReviewLevel)
+		if err != nil {
+			return nil, err
+		}
+		policy.thresholds = thresholds
+	}
+	if config.Advisory {
+		policy.mode = PolicyAdvisory
+	}
+	if policy.mode == "" {
+		policy.mode = PolicyEnforce
+	}
+	logDebug("ℹ️【newPolicy】卡点策略：mode=%s，thresholds=%v，overrides=%d条，min_confidence=%.2f\n",
+		policy.mode, policy.thresholds, len(policy.overrides), policy.minConfidence)
+	return policy, nil
+}
+
+// validatePolicy 校验卡点策略配置
+func validatePolicy(conf PolicyConfig) error {
+	switch conf.Mode {
+	case "", PolicyEnforce, PolicyAdvisory:
+	default:
+		return fmt.Errorf("policy.mode无效：%s（可选：enforce/advisory）", conf.Mode)
+	}
+	if conf.MinConfidence < 0 || conf.MinConfidence > 1 {
+		return fmt.Errorf("policy.min_confidence必须在0~1之间：%v", conf.MinConfidence)
+	}
+	if err := validateThresholds("policy.thresholds", conf.Thresholds); err != nil {
+		return err
+	}
+	for i, override := range conf.Overrides {
+		if err := validateThresholds(fmt.Sprintf("policy.overrides[%d].thresholds", i), override.Thresholds); err != nil {
+			return err
+		}
+		if override.Promote < 0 {
+			return fmt.Errorf("policy.overrides[%d].promote不能为负数", i)
+		}
+	}
+	return nil
+}
+
+// validateThresholds 校验阈值配置的等级和数量
+func validateThresholds(name string, thresholds map[string]int) error {
+	for level, max := range thresholds {
+		if levelRank(level) < 0 {
+			return fmt.Errorf("%s中的等级无效：%s（可选：block/high/medium/suggest）", name, level)
+		}
+		if max < 0 {
+			return fmt.Errorf("%s.%s不能为负数", name, level)
+		}
+	}
+	return nil
+}
+
+// Evaluate 按策略判定问题列表，会就地提升命中覆盖规则的问题等级、标记低置信度问题；基线中的存量问题不计数，
+// 密钥扫描发现的问题不受基线、阈值和豁免规则影响，出现即阻断
+func (p *Policy) Evaluate(issues []BlockIssue) PolicyDecision {
+	decision := PolicyDecision{Mode: p.mode}
+
+	type scope struct {
+		name       string
+		thresholds map[string]int
+		issues     map[string][]BlockIssue
+	}
+	global := &scope{name: "global", thresholds: p.thresholds, issues: make(map[string][]BlockIssue)}
+	secrets := &scope{name: "secrets", thresholds: map[string]int{LevelBlock: 0}, issues: make(map[string][]BlockIssue)}
+	scopes := []*scope{global, secrets}
+	overrideScopes := make(map[int]*scope)
+
+	for i := range issues {
+		issue := &issues[i]
+		index, override := p.match(*issue)
+		if override != nil && override.Promote > 0 {
+			if promoted := promoteLevel(issue.Level, override.Promote); promoted != issue.Level {
+				logDebug("⬆️【Policy】%s:%s的问题等级由%s提升为%s\n", issue.File, issue.Line, issue.Level, promoted)
+				issue.PromotedFrom = issue.Level
+				issue.Level = promoted
+			}
+		}
+		if issue.Secret {
+			secrets.issues[issue.Level] = append(secrets.issues[issue.Level], *issue)
+			continue
+		}
+		if issue.Known {
+			continue
+		}
+		if override != nil && override.Exempt {
+			continue
+		}
+		if p.lowConfidence(*issue) {
+			logDebug("ℹ️【Policy】%s:%s的问题置信度%.2f低于阈值%.2f，仅提示不卡点\n", issue.File, issue.Line, issue.Confidence, p.minConfidence)
+			issue.Advisory = true
+			continue
+		}
+
+		target := global
+		if override != nil && len(override.Thresholds) > 0 {
+			if overrideScopes[index] == nil {
+				overrideScopes[index] = &scope{
+					name:       strings.Join(append(append([]string{}, override.Paths...), override.Categories...), ","),
+					thresholds: override.Thresholds,
+					issues:     make(map[string][]BlockIssue),
+				}
+				scopes = append(scopes, overrideScopes[index])
+			}
+			target = overrideScopes[index]
+		}
+		target.issues[issue.Level] = append(target.issues[issue.Level], *issue)
+	}
+
+	for _, s := range scopes {
+		for _, level := range levelOrder {
+			max, ok := s.thresholds[level]
+			if !ok || len(s.issues[level]) <= max {
+				continue
+			}
+			decision.Blocked = true
+			decision.Violations = append(decision.Violations, PolicyViolation{Scope: s.name, Level: level, Count: len(s.issues[level]), Max: max})
+			decision.Issues = append(decision.Issues, s.issues[level]...)
+		}
+	}
+	return decision
+}
+
+// lowConfidence 判断block/high级问题的置信度是否低于阈值
+func (p *Policy) lowConfidence(issue BlockIssue) bool {
+	if p.minConfidence <= 0 || issue.Confidence <= 0 {
+		return false
+	}
+	return (issue.Level == LevelBlock || issue.Level == LevelHigh) && issue.Confidence < p.minConfidence
+}
+
+// match 查找问题命中的第一条覆盖规则
+func (p *Policy) match(issue BlockIssue) (int, *PolicyOverride) {
+	for i := range p.overrides {
+		override := &p.overrides[i]
+		if len(override.Paths) > 0 && !matchAnyGlob(override.Paths, issue.File) {
+			continue
+		}
+		if len(override.Categories) > 0 && !matchAnyCategory(issue, override.Categories) {
+			continue
+		}
+		return i, override
+	}
+	return -1, nil
+}
+
+// Reason 违反策略的原因描述
+func (d PolicyDecision) Reason(locale string) string {
+	var reasons []string
+	for _, v := range d.Violations {
+		reason := tr(locale, "policy_violation", v.Level, v.Count, v.Max)
+		if v.Scope != "global" {
+			reason += "[" + v.Scope + "]"
+		}
+		reasons = append(reasons, reason)
+	}
+	return strings.Join(reasons, tr(locale, "list_separator"))
+}
+
+// levelRank 等级在levelOrder中的位置，未知等级返回-1
+func levelRank(level string) int {
+	for i, l := range levelOrder {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// promoteLevel 将等级提升steps级，最高为block；未知等级不提升
+func promoteLevel(level string, steps int) string {
+	rank := levelRank(level)
+	if rank < 0 {
+		return level
+	}
+	rank -= steps
+	if rank < 0 {
+		rank = 0
+	}
+	return levelOrder[rank]
+}
+
+// matchAnyCategory 判断问题是否命中任一规则ID/CWE编号/OWASP分类/分类关键字（分类关键字匹配问题描述），
+// OWASP分类可省略年份，如A03
+func matchAnyCategory(issue BlockIssue, categories []string) bool {
+	for _, category := range categories {
+		for _, id := range []string{issue.RuleID, issue.CWE, issue.OWASP} {
+			if id != "" && strings.EqualFold(category, id) {
+				return true
+			}
+		}
+		if issue.OWASP != "" && strings.EqualFold(category, strings.SplitN(issue.OWASP, ":", 2)[0]) {
+			return true
+		}
+		if strings.Contains(strings.ToLower(issue.Issue), strings.ToLower(category)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package main
+
+import "testing"
+
+// TestPolicyEvaluate 测试等级阈值、路径覆盖、等级提升、豁免、低置信度、密钥泄漏以及存量问题不计数
+func TestPolicyEvaluate(t *testing.T) {
+	config := Config{ReviewLevel: LevelBlock}
+	config.File.Policy = PolicyConfig{
+		Thresholds: map[string]int{LevelBlock: 0, LevelHigh: 2},
+		Overrides: []PolicyOverride{
+			{Paths: []string{"docs/**", "scripts/"}, Exempt: true},
+			{Paths: []string{"payment/**"}, Promote: 1, Thresholds: map[string]int{LevelBlock: 0, LevelHigh: 0}},
+			{Categories: []string{"SQL注入"}, Promote: 2},
+		},
+		MinConfidence: 0.6,
+	}
+	policy, err := newPolicy(config, false)
+	if err != nil {
+		t.Fatalf("newPolicy失败：%v", err)
+	}
+
+	tests := []struct {
+		name       string
+		issues     []BlockIssue
+		blocked    bool
+		violations int
+	}{
+		{"high未超过阈值", []BlockIssue{
+			{Level: LevelHigh, File: "a.go"}, {Level: LevelHigh, File: "b.go"},
+		}, false, 0},
+		{"high超过阈值", []BlockIssue{
+			{Level: LevelHigh, File: "a.go"}, {Level: LevelHigh, File: "b.go"}, {Level: LevelHigh, File: "c.go"},
+		}, true, 1},
+		{"低置信度只提示", []BlockIssue{
+			{Level: LevelBlock, File: "a.go", Confidence: 0.4},
+		}, false, 0},
+		{"置信度达到阈值", []BlockIssue{
+			{Level: LevelBlock, File: "a.go", Confidence: 0.6},
+		}, true, 1},
+		{"存量问题不计数", []BlockIssue{
+			{Level: LevelBlock, File: "a.go", Known: true},
+		}, false, 0},
+		{"豁免路径", []BlockIssue{
+			{Level: LevelBlock, File: "scripts/deploy.sh"},
+		}, false, 0},
+		{"密钥泄漏不受豁免路径影响", []BlockIssue{
+			{Level: LevelBlock, File: "scripts/deploy.sh", Secret: true},
+		}, true, 1},
+		{"基线中的密钥泄漏仍阻断", []BlockIssue{
+			{Level: LevelBlock, File: "conf/aliyun.go", Secret: true, Known: true},
+		}, true, 1},
+		{"受保护路径medium提升为high并单独计数", []BlockIssue{
+			{Level: LevelMedium, File: "payment/card.go"},
+		}, true, 1},
+		{"分类提升", []BlockIssue{
+			{Level: LevelMedium, File: "user/dao.go", Issue: "存在SQL注入风险"},
+		}, true, 1},
+	}
+	for _, tt := range tests {
+		decision := policy.Evaluate(tt.issues)
+		if decision.Blocked != tt.blocked || len(decision.Violations) != tt.violations {
+			t.Errorf("%s：blocked=%v violations=%v，期望blocked=%v violations=%d", tt.name, decision.Blocked, decision.Violations, tt.blocked, tt.violations)
+		}
+	}
+
+	issues := []BlockIssue{{Level: LevelMedium, File: "payment/card.go"}}
+	policy.Evaluate(issues)
+	if issues[0].Level != LevelHigh || issues[0].PromotedFrom != LevelMedium {
+		t.Errorf("等级提升结果 = %s（原%s），期望high（原medium）", issues[0].Level, issues[0].PromotedFrom)
+	}
+
+	// 显式指定--level时以命令行为准：medium及以上出现即阻断
+	config.ReviewLevel = LevelMedium
+	policy, err = newPolicy(config, true)
+	if err != nil {
+		t.Fatalf("newPolicy失败：%v", err)
+	}
+	if decision := policy.Evaluate([]BlockIssue{{Level: LevelMedium, File: "a.go"}}); !decision.Blocked {
+		t.Errorf("--level medium时medium问题应阻断")
+	}
+}
+
+// TestNewPolicyUnknownLevel 测试未知的--level返回错误，而不是让所有等级都阻断
+func TestNewPolicyUnknownLevel(t *testing.T) {
+	if err := validateLevel("hgih"); err == nil {
+		t.Errorf("validateLevel(hgih)应返回错误")
+	}
+	for _, explicit := range []bool{true, false} {
+		if _, err := newPolicy(Config{ReviewLevel: "hgih"}, explicit); err == nil {
+			t.Errorf("newPolicy(--level hgih, explicit=%v)应返回错误", explicit)
+		}
+	}
+}
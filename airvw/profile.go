@@ -0,0 +1,36 @@
+package main
+
+import "fmt"
+
+// 评审模式
+const (
+	ProfileDefault  = "default"  // 通用评审（默认）
+	ProfileSecurity = "security" // 安全评审：使用安全prompt，问题标注CWE编号和OWASP分类，追加安全扫描工具
+)
+
+// securityCommonLinters 安全评审模式下所有语言都追加执行的扫描工具，未安装时跳过
+var securityCommonLinters = []LinterSpec{
+	{Name: "semgrep", Command: "semgrep", Args: []string{"scan", "--config", "p/security-audit", "--metrics", "off", "--quiet", "--emacs", "{file}"}, Optional: true},
+}
+
+// validateProfile 校验评审模式
+func validateProfile(profile string) error {
+	switch profile {
+	case "", ProfileDefault, ProfileSecurity:
+		return nil
+	}
+	return fmt.Errorf("评审模式无效：%s（可选：default/security）", profile)
+}
+
+// applyProfile 按评审模式调整评审流程：安全评审模式下在语言声明的静态检查之后追加安全扫描工具
+func applyProfile(process ReviewProcess, profile string) ReviewProcess {
+	spec, ok := process.(*LanguageSpec)
+	if !ok || profile != ProfileSecurity {
+		return process
+	}
+	secured := *spec
+	secured.Linters = append(append([]LinterSpec{}, spec.Linters...), spec.SecurityLinters...)
+	secured.Linters = append(secured.Linters, securityCommonLinters...)
+	logDebug("ℹ️【applyProfile】安全评审模式，追加%d个安全扫描工具\n", len(secured.Linters)-len(spec.Linters))
+	return &secured
+}
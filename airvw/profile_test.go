@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSecurityProfilePrompt 测试安全评审模式叠加安全模板：替换角色和评审维度、要求标注CWE/OWASP，语言无专属安全模板时使用通用安全维度
+func TestSecurityProfilePrompt(t *testing.T) {
+	defer func(old *PromptSet) { prompts = old }(prompts)
+	prompts = newPromptSet("", ProfileSecurity)
+
+	input := PromptInput{DiffFiles: map[string]string{"dao/user.go": "@@ -1,1 +1,2 @@\n package dao\n+var q = \"select 1\"\n"}}
+	prompt, err := prompts.Render("golang", input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"应用安全工程师", "InsecureSkipVerify", "[CWE编号][OWASP分类]", "【安全评审要求】"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("安全评审prompt中缺少%q：\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "内存优化") {
+		t.Errorf("安全评审prompt不应包含通用评审维度：\n%s", prompt)
+	}
+	if version := prompts.Version("golang"); version != "security-1.0" {
+		t.Errorf("Version() = %s，期望security-1.0", version)
+	}
+
+	prompt, err = prompts.Render("swift", input)
+	if err != nil || !strings.Contains(prompt, "Keychain") {
+		t.Errorf("swift安全评审prompt缺少语言专属维度：%v\n%s", err, prompt)
+	}
+	prompt, err = prompts.Render(genericPromptName, PromptInput{DiffFiles: input.DiffFiles, Language: "Lua"})
+	if err != nil || !strings.Contains(prompt, "不安全的反序列化") || !strings.Contains(prompt, "Lua代码") {
+		t.Errorf("通用语言安全评审prompt不正确：%v\n%s", err, prompt)
+	}
+
+	// 默认模式不受影响
+	prompt, err = newPromptSet("", ProfileDefault).Render("golang", input)
+	if err != nil || strings.Contains(prompt, "CWE") || !strings.Contains(prompt, "内存优化") {
+		t.Errorf("默认模式prompt不正确：%v\n%s", err, prompt)
+	}
+}
+
+// TestApplyProfile 测试安全评审模式追加语言声明的安全扫描工具和通用扫描工具，且不修改注册表中的语言声明
+func TestApplyProfile(t *testing.T) {
+	golang := GetReviewProcess("golang").(*LanguageSpec)
+	secured := applyProfile(golang, ProfileSecurity).(*LanguageSpec)
+	var names []string
+	for _, linter := range secured.Linters {
+		names = append(names, linter.Name)
+	}
+	if strings.Join(names, ",") != "golangci-lint,gosec,semgrep" {
+		t.Errorf("安全评审模式的检查工具 = %v", names)
+	}
+	if len(golang.Linters) != 1 {
+		t.Errorf("applyProfile修改了注册表中的语言声明：%v", golang.Linters)
+	}
+	if applyProfile(golang, ProfileDefault) != ReviewProcess(golang) {
+		t.Errorf("默认模式不应调整评审流程")
+	}
+	if err := validateProfile("pentest"); err == nil {
+		t.Errorf("validateProfile(pentest)应返回错误")
+	}
+}
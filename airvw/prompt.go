@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed prompts
+var builtinPrompts embed.FS
+
+// promptBaseName 通用prompt骨架模板文件名（不含扩展名）
+const promptBaseName = "base"
+
+// genericPromptName 没有专属模板的语言使用的通用模板名称
+const genericPromptName = "generic"
+
+// PromptInput prompt渲染输入
+type PromptInput struct {
+	DiffFiles   map[string]string // 文件路径 -> diff内容
+	Changes     []FileChange      // 重命名/删除的文件
+	LintResults map[string]string // 文件路径 -> 静态检查结果
+	Rules       []ReviewRule      // 适用的团队自定义规则
+	Locale      string            // 输出语言区域
+	Language    string            // 评审语言的展示名，供通用模板使用
+	Dimensions  string            // 语言声明中的评审维度，供通用模板使用
+}
+
+// PromptFile prompt模板中的单个待评审文件
+type PromptFile struct {
+	Path    string
+	OldPath string // 重命名前的路径，未重命名时为空
+	Lint    string
+	Diff    string // 标注了新文件行号的diff
+	RawDiff string // 原始unified diff
+}
+
+// promptData prompt模板渲染数据
+type promptData struct {
+	Files      []PromptFile
+	Changes    []FileChange
+	Rules      []ReviewRule
+	Locale     string
+	Language   string
+	Dimensions string
+	Levels     struct{ Block, High, Medium, Suggest string }
+}
+
+// PromptSet prompt模板集合：覆盖目录中的模板叠加在内置模板之上，评审模式的模板叠加在通用模板之上
+type PromptSet struct {
+	dir     string
+	profile string
+	cache   map[string]*template.Template
+	hash    map[string]string
+}
+
+// prompts 全局prompt模板集合，main中根据--prompt-dir和--profile初始化
+var prompts = newPromptSet("", "")
+
+// newPromptSet 创建prompt模板集合，dir为空时仅使用内置模板，profile为空或default时使用通用评审模板
+func newPromptSet(dir, profile string) *PromptSet {
+	if profile == ProfileDefault {
+		profile = ""
+	}
+	return &PromptSet{
+		dir:     dir,
+		profile: profile,
+		cache:   make(map[string]*template.Template),
+		hash:    make(map[string]string),
+	}
+}
+
+// Render 渲染指定语言的评审prompt，禁止发送的文件不会出现在prompt中，diff和规则检查结果经过脱敏
+func (p *PromptSet) Render(name string, input PromptInput) (string, error) {
+	data := promptData{Rules: input.Rules, Locale: input.Locale, Language: input.Language, Dimensions: input.Dimensions}
+	data.Levels.Block, data.Levels.High, data.Levels.Medium, data.Levels.Suggest = LevelBlock, LevelHigh, LevelMedium, LevelSuggest
+	data.Files = promptFiles(input)
+	for _, change := range input.Changes {
+		if !redactor.Excluded(change.Path) && (change.OldPath == "" || !redactor.Excluded(change.OldPath)) {
+			data.Changes = append(data.Changes, change)
+		}
+	}
+
+	return p.Execute(name, promptBaseName, data)
+}
+
+// promptFiles 生成prompt中的待评审文件列表：按路径排序，跳过禁止发送给模型的文件，内容经过脱敏
+func promptFiles(input PromptInput) []PromptFile {
+	// 按文件路径排序，保证同一diff渲染出的prompt稳定
+	var paths []string
+	for file := range input.DiffFiles {
+		if redactor.Excluded(file) {
+			continue
+		}
+		paths = append(paths, file)
+	}
+	sort.Strings(paths)
+
+	var files []PromptFile
+	for _, file := range paths {
+		files = append(files, PromptFile{
+			Path:    file,
+			OldPath: renamedFrom(input.Changes, file),
+			Lint:    redactor.Redact(input.LintResults[file]),
+			Diff:    redactor.Redact(annotateDiff(input.DiffFiles[file])),
+			RawDiff: redactor.Redact(input.DiffFiles[file]),
+		})
+	}
+	return files
+}
+
+// Execute 渲染指定prompt模板集合中的入口模板，如verify模板集合中的verify
+func (p *PromptSet) Execute(name, entry string, data interface{}) (string, error) {
+	tmpl, err := p.load(name)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, entry, data); err != nil {
+		return "", fmt.Errorf("渲染%s prompt失败：%w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// Has 判断内置模板或覆盖目录中是否存在指定名称的prompt模板
+func (p *PromptSet) Has(name string) bool {
+	if _, err := builtinPrompts.ReadFile("prompts/" + name + ".tmpl"); err == nil {
+		return true
+	}
+	return p.dir != "" && fileExists(filepath.Join(p.dir, name+".tmpl"))
+}
+
+// Version 指定语言prompt的版本号：模板中定义的version，使用了覆盖模板时追加内容摘要
+func (p *PromptSet) Version(name string) string {
+	tmpl, err := p.load(name)
+	if err != nil {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "version", nil); err != nil {
+		return ""
+	}
+	version := strings.TrimSpace(buf.String())
+	if hash := p.hash[name]; hash != "" {
+		version += "+custom." + hash
+	}
+	return version
+}
+
+// load 加载并缓存prompt模板，按「内置base → 内置语言模板 → 覆盖目录base → 覆盖目录语言模板」顺序叠加，
+// 指定评审模式时，每组base和语言模板之后再叠加该模式子目录中的base和语言模板（如security/base.tmpl、security/golang.tmpl），
+// 后加载的同名define覆盖先加载的，覆盖文件只需重新定义需要修改的部分
+func (p *PromptSet) load(name string) (*template.Template, error) {
+	if tmpl, ok := p.cache[name]; ok {
+		return tmpl, nil
+	}
+
+	files := []string{promptBaseName, name}
+	if p.profile != "" {
+		files = append(files, p.profile+"/"+promptBaseName, p.profile+"/"+name)
+	}
+	type layer struct{ source, text string }
+	var layers []layer
+	found := false
+	for _, file := range files {
+		if data, err := builtinPrompts.ReadFile("prompts/" + file + ".tmpl"); err == nil {
+			layers = append(layers, layer{"内置模板prompts/" + file + ".tmpl", string(data)})
+			found = found || file == name
+		}
+	}
+	builtinCount := len(layers)
+	if p.dir != "" {
+		for _, file := range files {
+			candidate := filepath.Join(p.dir, file+".tmpl")
+			if !fileExists(candidate) {
+				continue
+			}
+			data, err := os.ReadFile(candidate)
+			if err != nil {
+				return nil, fmt.Errorf("读取prompt模板%s失败：%w", candidate, err)
+			}
+			logDebug("ℹ️【PromptSet】使用覆盖prompt模板：%s\n", candidate)
+			layers = append(layers, layer{candidate, string(data)})
+			found = found || file == name
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("未找到%s的prompt模板", name)
+	}
+
+	tmpl := template.New(name).Funcs(template.FuncMap{"join": strings.Join})
+	digest := sha256.New()
+	for i, l := range layers {
+		var err error
+		if tmpl, err = tmpl.Parse(l.text); err != nil {
+			return nil, fmt.Errorf("解析prompt模板%s失败：%w", l.source, err)
+		}
+		if i >= builtinCount {
+			digest.Write([]byte(l.text))
+		}
+	}
+
+	if len(layers) > builtinCount {
+		p.hash[name] = hex.EncodeToString(digest.Sum(nil))[:8]
+	}
+	p.cache[name] = tmpl
+	return tmpl, nil
+}
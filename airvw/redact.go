@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// RedactionConfig 数据治理配置：控制哪些内容可以发送给模型
+type RedactionConfig struct {
+	ExcludePaths []string           `json:"exclude_paths"` // 永不发送给模型的路径（glob），仍参与本地静态检查和密钥扫描
+	Patterns     []RedactionPattern `json:"patterns"`      // 发送前额外脱敏的内容，如手机号、身份证号
+	AuditLog     string             `json:"audit_log"`     // 审计日志文件，每次调用模型前追加一条JSON记录
+}
+
+// RedactionPattern 脱敏规则，命中的内容替换为[REDACTED:名称]
+type RedactionPattern struct {
+	Name    string `json:"name"`    // 名称；使用内置规则（phone/id_card/email）时可省略pattern
+	Pattern string `json:"pattern"` // 正则表达式
+}
+
+// builtinRedactionPatterns 内置的个人信息脱敏规则
+var builtinRedactionPatterns = map[string]string{
+	"phone":   `(?:\+?86[- ]?)?\b1[3-9]\d{9}\b`,
+	"id_card": `\b[1-9]\d{5}(?:18|19|20)\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])\d{3}[\dXx]\b`,
+	"email":   `\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`,
+}
+
+// Redactor 发送给模型前的内容治理：排除路径、脱敏密钥和配置的敏感信息
+type Redactor struct {
+	excludes []string
+	patterns []namedPattern
+}
+
+// namedPattern 编译后的脱敏规则
+type namedPattern struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// redactor 全局脱敏器，main中根据配置文件初始化；未配置时只脱敏密钥
+var redactor = &Redactor{}
+
+// newRedactor 根据配置创建脱敏器
+func newRedactor(conf RedactionConfig) (*Redactor, error) {
+	r := &Redactor{excludes: conf.ExcludePaths}
+	for i, p := range conf.Patterns {
+		if p.Name == "" {
+			return nil, fmt.Errorf("redaction.patterns[%d].name不能为空", i)
+		}
+		expr := p.Pattern
+		if expr == "" {
+			builtin, ok := builtinRedactionPatterns[p.Name]
+			if !ok {
+				return nil, fmt.Errorf("redaction.patterns[%d]未配置pattern，且%s不是内置规则（可选：phone/id_card/email）", i, p.Name)
+			}
+			expr = builtin
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("redaction.patterns[%d].pattern无效：%w", i, err)
+		}
+		r.patterns = append(r.patterns, namedPattern{name: p.Name, pattern: re})
+	}
+	return r, nil
+}
+
+// Excluded 判断文件是否禁止发送给模型
+func (r *Redactor) Excluded(path string) bool {
+	return len(r.excludes) > 0 && matchAnyGlob(r.excludes, path)
+}
+
+// ExcludedFiles 变更文件中禁止发送给模型的文件，按路径排序
+func (r *Redactor) ExcludedFiles(diffFiles map[string]string) []string {
+	var excluded []string
+	for file := range diffFiles {
+		if r.Excluded(file) {
+			excluded = append(excluded, file)
+		}
+	}
+	sort.Strings(excluded)
+	return excluded
+}
+
+// Redact 脱敏发送给模型的文本：先脱敏密钥，再按配置的规则脱敏敏感信息
+func (r *Redactor) Redact(text string) string {
+	text = redactSecrets(text)
+	for _, p := range r.patterns {
+		text = p.pattern.ReplaceAllLiteralString(text, "[REDACTED:"+p.name+"]")
+	}
+	return text
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestNewRedactor 测试脱敏规则校验：名称必填、未配置pattern时只能使用内置规则、正则必须合法
+func TestNewRedactor(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern RedactionPattern
+		wantErr bool
+	}{
+		{"内置规则", RedactionPattern{Name: "phone"}, false},
+		{"自定义规则", RedactionPattern{Name: "order_no", Pattern: `ORD\d{12}`}, false},
+		{"缺少名称", RedactionPattern{Pattern: `\d+`}, true},
+		{"未知内置规则", RedactionPattern{Name: "passport"}, true},
+		{"正则无效", RedactionPattern{Name: "bad", Pattern: `(`}, true},
+	}
+	for _, tt := range tests {
+		_, err := newRedactor(RedactionConfig{Patterns: []RedactionPattern{tt.pattern}})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s：err=%v，期望出错=%v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+// TestRedactorPrompt 测试禁止发送的文件不出现在prompt中，个人信息按规则脱敏
+func TestRedactorPrompt(t *testing.T) {
+	defer func(old *Redactor) { redactor = old }(redactor)
+	var err error
+	redactor, err = newRedactor(RedactionConfig{
+		ExcludePaths: []string{"testdata/customers/**", "algo/pricing.go"},
+		Patterns:     []RedactionPattern{{Name: "phone"}, {Name: "id_card"}, {Name: "order_no", Pattern: `ORD\d{12}`}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diffFiles := map[string]string{
+		"user/service.go":              "@@ -1,1 +1,2 @@\n package user\n+var demo = \"13812345678 110101199003071234 ORD202401010001\"\n",
+		"testdata/customers/list.json": "@@ -0,0 +1,1 @@\n+{\"name\": \"张三\"}\n",
+		"algo/pricing.go":              "@@ -1,1 +1,2 @@\n package algo\n+func secretFormula() {}\n",
+	}
+	prompt, err := prompts.Render("golang", PromptInput{
+		DiffFiles: diffFiles,
+		Changes:   []FileChange{{Status: ChangeRemoved, Path: "algo/pricing_v1.go"}, {Status: ChangeRemoved, Path: "testdata/customers/old.json"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leaked := range []string{"testdata/customers", "algo/pricing.go", "secretFormula", "13812345678", "110101199003071234", "ORD202401010001"} {
+		if strings.Contains(prompt, leaked) {
+			t.Errorf("prompt中包含不应发送的内容%q：\n%s", leaked, prompt)
+		}
+	}
+	for _, want := range []string{"user/service.go", "algo/pricing_v1.go", "[REDACTED:phone]", "[REDACTED:id_card]", "[REDACTED:order_no]"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt中缺少%q：\n%s", want, prompt)
+		}
+	}
+	if excluded := redactor.ExcludedFiles(diffFiles); strings.Join(excluded, ",") != "algo/pricing.go,testdata/customers/list.json" {
+		t.Errorf("ExcludedFiles() = %v", excluded)
+	}
+}
+
+// TestAuditModelCall 测试审计日志按JSON Lines追加，记录文件字节数、排除的文件和脱敏次数
+func TestAuditModelCall(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "airvw.jsonl")
+	config := Config{AuditLog: path, MRID: 42}
+	prompt := "user/service.go\n+var demo = \"[REDACTED:phone] [REDACTED:phone] [REDACTED:ALIYUN-AK]\"\n"
+	record := AuditRecord{Model: "qwen3-coder-plus", Purpose: AuditPurposeReview, Files: []AuditFile{{Path: "user/service.go", Bytes: 64}}, Excluded: []string{"algo/pricing.go"}}
+	for i := 0; i < 2; i++ {
+		if err := auditModelCall(config, record, prompt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("审计日志行数 = %d，期望2", len(lines))
+	}
+	var got AuditRecord
+	if err := json.Unmarshal([]byte(lines[1]), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.RunID != auditRunID || got.Provider != modelProvider || got.MRID != 42 || got.PromptBytes != len(prompt) || len(got.PromptSHA256) != 64 {
+		t.Errorf("审计记录 = %+v", got)
+	}
+	if got.Redactions["phone"] != 2 || got.Redactions["ALIYUN-AK"] != 1 || len(got.Excluded) != 1 || got.Files[0].Bytes != 64 {
+		t.Errorf("审计记录 = %+v", got)
+	}
+
+	if err := auditModelCall(Config{}, record, prompt); err != nil {
+		t.Errorf("未配置审计日志时不应出错：%v", err)
+	}
+}
+
+// TestApplyRedactionConfig 测试命令行的审计日志路径优先，脱敏规则无效时返回错误
+func TestApplyRedactionConfig(t *testing.T) {
+	defer func(old *Redactor) { redactor = old }(redactor)
+
+	config := Config{AuditLog: "cli.jsonl"}
+	config.File.Redaction = RedactionConfig{AuditLog: "file.jsonl", Patterns: []RedactionPattern{{Name: "phone"}}}
+	if err := applyRedactionConfig(&config); err != nil {
+		t.Fatal(err)
+	}
+	if config.AuditLog != "cli.jsonl" || len(redactor.patterns) != 1 {
+		t.Errorf("AuditLog = %s，脱敏规则数 = %d", config.AuditLog, len(redactor.patterns))
+	}
+
+	config = Config{}
+	config.File.Redaction = RedactionConfig{AuditLog: "file.jsonl", Patterns: []RedactionPattern{{Name: "card"}}}
+	if err := applyRedactionConfig(&config); err == nil {
+		t.Error("未知的内置脱敏规则应返回错误")
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ReviewRule 团队自定义评审规则（配置文件rules数组元素）
+type ReviewRule struct {
+	ID          string   `json:"id"`           // 规则ID，如PAY-001，会回显在问题的rule_id中
+	Description string   `json:"description"`  // 规则描述
+	Severity    string   `json:"severity"`     // 命中时的问题等级：block/high/medium/suggest，默认medium
+	Paths       []string `json:"paths"`        // 适用路径（glob），为空表示所有文件
+	Language    string   `json:"language"`     // 适用语言，为空表示所有语言
+	GoodExample string   `json:"good_example"` // 正例
+	BadExample  string   `json:"bad_example"`  // 反例
+}
+
+// validateRules 校验自定义规则配置，并补全默认等级
+func validateRules(rules []ReviewRule) error {
+	seen := make(map[string]bool)
+	for i := range rules {
+		rule := &rules[i]
+		if rule.ID == "" {
+			return fmt.Errorf("第%d条自定义规则缺少id", i+1)
+		}
+		if seen[rule.ID] {
+			return fmt.Errorf("自定义规则id重复：%s", rule.ID)
+		}
+		seen[rule.ID] = true
+		if rule.Description == "" {
+			return fmt.Errorf("自定义规则%s缺少description", rule.ID)
+		}
+		switch rule.Severity {
+		case "":
+			rule.Severity = LevelMedium
+		case LevelBlock, LevelHigh, LevelMedium, LevelSuggest:
+		default:
+			return fmt.Errorf("自定义规则%s的severity无效：%s（可选：block/high/medium/suggest）", rule.ID, rule.Severity)
+		}
+	}
+	return nil
+}
+
+// applicableRules 筛选适用于本次评审语言和变更文件的规则
+func applicableRules(rules []ReviewRule, language string, diffFiles map[string]string) []ReviewRule {
+	var result []ReviewRule
+	for _, rule := range rules {
+		if rule.Language != "" && languageDisplayName(rule.Language) != languageDisplayName(language) {
+			continue
+		}
+		if len(rule.Paths) > 0 {
+			matched := false
+			for file := range diffFiles {
+				if matchAnyGlob(rule.Paths, file) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+		result = append(result, rule)
+	}
+	return result
+}
+
+// applyRuleSeverity 命中自定义规则的问题统一为配置中的规则ID写法，并以规则配置的等级为准（防止模型给出其他等级）；
+// 规则限定了paths时只调整适用路径内的问题
+func applyRuleSeverity(issues []BlockIssue, rules []ReviewRule) {
+	for i := range issues {
+		for _, rule := range rules {
+			if !strings.EqualFold(rule.ID, issues[i].RuleID) {
+				continue
+			}
+			issues[i].RuleID = rule.ID
+			if len(rule.Paths) > 0 && !matchAnyGlob(rule.Paths, issues[i].File) {
+				logDebug("ℹ️【applyRuleSeverity】%s:%s不在规则%s的适用路径内，保留等级%s\n", issues[i].File, issues[i].Line, rule.ID, issues[i].Level)
+				break
+			}
+			if rule.Severity != issues[i].Level {
+				logDebug("ℹ️【applyRuleSeverity】%s:%s命中规则%s，等级由%s调整为%s\n", issues[i].File, issues[i].Line, rule.ID, issues[i].Level, rule.Severity)
+				issues[i].Level = rule.Severity
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+// TestApplyRuleSeverity 测试规则ID统一为配置中的写法，等级只在规则的适用路径内调整
+func TestApplyRuleSeverity(t *testing.T) {
+	rules := []ReviewRule{
+		{ID: "PAY-001", Severity: LevelBlock, Paths: []string{"payment/**"}},
+		{ID: "LOG-001", Severity: LevelSuggest},
+	}
+	issues := []BlockIssue{
+		{Level: LevelHigh, File: "payment/refund.go", RuleID: "pay-001"},
+		{Level: LevelHigh, File: "order/create.go", RuleID: "Pay-001"},
+		{Level: LevelMedium, File: "order/create.go", RuleID: "log-001"},
+		{Level: LevelSuggest, File: "order/create.go", RuleID: "log-001"},
+		{Level: LevelHigh, File: "order/create.go", RuleID: "OTHER-1"},
+	}
+	applyRuleSeverity(issues, rules)
+
+	want := []struct{ level, ruleID string }{
+		{LevelBlock, "PAY-001"},
+		{LevelHigh, "PAY-001"},
+		{LevelSuggest, "LOG-001"},
+		{LevelSuggest, "LOG-001"},
+		{LevelHigh, "OTHER-1"},
+	}
+	for i, w := range want {
+		if issues[i].Level != w.level || issues[i].RuleID != w.ruleID {
+			t.Errorf("第%d个问题 = %s/%s，期望%s/%s", i+1, issues[i].Level, issues[i].RuleID, w.level, w.ruleID)
+		}
+	}
+}
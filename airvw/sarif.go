@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// sarifSchema SARIF 2.1.0 schema地址
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// sarifLevels 问题等级对应的SARIF结果级别
+var sarifLevels = map[string]string{LevelBlock: "error", LevelHigh: "error", LevelMedium: "warning", LevelSuggest: "note"}
+
+// sarifSecuritySeverity 问题等级对应的security-severity（0~10），代码扫描平台据此划分critical/high/medium/low
+var sarifSecuritySeverity = map[string]float64{LevelBlock: 9.0, LevelHigh: 7.5, LevelMedium: 5.0, LevelSuggest: 2.0}
+
+// SARIF 2.1.0输出结构，只包含用到的字段
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool              `json:"tool"`
+	Results    []sarifResult          `json:"results"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string              `json:"id"`
+	ShortDescription sarifMessage        `json:"shortDescription"`
+	HelpURI          string              `json:"helpUri,omitempty"`
+	Properties       sarifRuleProperties `json:"properties"`
+}
+
+type sarifRuleProperties struct {
+	SecuritySeverity string   `json:"security-severity"`
+	Tags             []string `json:"tags,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID        string                 `json:"ruleId"`
+	Level         string                 `json:"level"`
+	Message       sarifMessage           `json:"message"`
+	Locations     []sarifLocation        `json:"locations"`
+	BaselineState string                 `json:"baselineState"`
+	Suppressions  []sarifSuppression     `json:"suppressions,omitempty"`
+	Properties    map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine int `json:"startLine"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// writeSARIF 将评审问题写入SARIF文件：基线中的存量问题标记为unchanged，airvw:ignore忽略的问题附带inSource抑制记录，
+// 无法定位到文件的问题不写入
+func writeSARIF(path string, config Config, result ReviewResult, issues []BlockIssue) error {
+	rules := make(map[string]*sarifRule)
+	severities := make(map[string]float64)
+	var results []sarifResult
+	add := func(issue BlockIssue, suppression *sarifSuppression) {
+		line, _ := strconv.Atoi(issue.Line)
+		if issue.File == "unknown" || line <= 0 {
+			logDebug("ℹ️【writeSARIF】问题未定位到文件，不写入SARIF：%s\n", issue.Issue)
+			return
+		}
+		ruleID := sarifRuleID(issue)
+		rule, ok := rules[ruleID]
+		if !ok {
+			rule = newSARIFRule(ruleID, issue)
+			rules[ruleID] = rule
+		}
+		// 同一规则下的问题等级不同时取最高的security-severity
+		if severity := sarifSecuritySeverity[issue.Level]; severity > severities[ruleID] {
+			severities[ruleID] = severity
+			rule.Properties.SecuritySeverity = fmt.Sprintf("%.1f", severity)
+		}
+
+		r := sarifResult{RuleID: ruleID, Level: sarifLevels[issue.Level], BaselineState: "new"}
+		r.Message.Text = issue.Issue
+		if issue.Suggestion != "" {
+			r.Message.Text += "\n" + issue.Suggestion
+		}
+		var location sarifLocation
+		location.PhysicalLocation.ArtifactLocation.URI = issue.File
+		location.PhysicalLocation.Region.StartLine = line
+		r.Locations = []sarifLocation{location}
+		if issue.Known {
+			r.BaselineState = "unchanged"
+		}
+		if suppression != nil {
+			r.Suppressions = []sarifSuppression{*suppression}
+		}
+		r.Properties = map[string]interface{}{"level": issue.Level}
+		if issue.Confidence > 0 {
+			r.Properties["confidence"] = issue.Confidence
+		}
+		if issue.OWASP != "" {
+			r.Properties["owasp"] = issue.OWASP
+		}
+		results = append(results, r)
+	}
+	for _, issue := range issues {
+		add(issue, nil)
+	}
+	for _, s := range result.Suppressed {
+		add(s.BlockIssue, &sarifSuppression{Kind: "inSource", Justification: strings.TrimSpace(s.Target + " " + s.Reason)})
+	}
+
+	driver := sarifDriver{Name: "airvw", InformationURI: "https://github.com/konglong87/airvw", Rules: []sarifRule{}}
+	for _, rule := range rules {
+		driver.Rules = append(driver.Rules, *rule)
+	}
+	sort.Slice(driver.Rules, func(i, j int) bool { return driver.Rules[i].ID < driver.Rules[j].ID })
+	if results == nil {
+		results = []sarifResult{}
+	}
+
+	report := sarifLog{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: driver},
+			Results: results,
+			Properties: map[string]interface{}{
+				"model":          result.Model,
+				"prompt_version": result.PromptVersion,
+				"profile":        firstNonEmpty(config.Profile, ProfileDefault),
+				"language":       config.Language,
+			},
+		}},
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("SARIF格式化失败：%w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("写入SARIF文件%s失败：%w", path, err)
+	}
+	return nil
+}
+
+// sarifRuleID 问题对应的SARIF规则：优先使用规则ID，其次CWE编号，都没有时按等级归类
+func sarifRuleID(issue BlockIssue) string {
+	if issue.RuleID != "" {
+		return issue.RuleID
+	}
+	if issue.CWE != "" {
+		return issue.CWE
+	}
+	return "airvw/" + issue.Level
+}
+
+// newSARIFRule 根据规则下的首个问题生成SARIF规则，CWE和OWASP分类写入tags
+func newSARIFRule(id string, issue BlockIssue) *sarifRule {
+	rule := &sarifRule{ID: id}
+	rule.ShortDescription.Text = id
+	if issue.CWE != "" {
+		number := strings.TrimPrefix(issue.CWE, "CWE-")
+		rule.HelpURI = "https://cwe.mitre.org/data/definitions/" + number + ".html"
+		rule.Properties.Tags = append(rule.Properties.Tags, "security", "external/cwe/cwe-"+number)
+	}
+	if issue.OWASP != "" {
+		rule.Properties.Tags = append(rule.Properties.Tags, "external/owasp/"+strings.ToLower(issue.OWASP))
+	}
+	return rule
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWriteSARIF 测试SARIF输出：规则按规则ID/CWE/等级归类并取最高的security-severity，存量问题和忽略的问题分别标记
+func TestWriteSARIF(t *testing.T) {
+	issues := []BlockIssue{
+		{Level: LevelBlock, File: "dao/user.go", Line: "42", Issue: "SQL注入", Suggestion: "使用参数化查询", CWE: "CWE-89", OWASP: "A03:2021", Confidence: 0.9},
+		{Level: LevelMedium, File: "dao/order.go", Line: "8", Issue: "SQL注入", CWE: "CWE-89", Known: true},
+		{Level: LevelHigh, File: "conf/aliyun.go", Line: "3", Issue: "疑似泄漏阿里云AccessKey ID：LTAI****", RuleID: "SECRET-ALIYUN-AK", Secret: true, CWE: "CWE-798"},
+		{Level: LevelSuggest, File: "util/str.go", Line: "5", Issue: "函数过长"},
+		{Level: LevelHigh, File: "unknown", Line: "0", Issue: "整体缺少单元测试"},
+	}
+	result := ReviewResult{
+		Model:      "qwen3-coder-plus",
+		Suppressed: []SuppressedIssue{{BlockIssue: BlockIssue{Level: LevelHigh, File: "api/proxy.go", Line: "15", Issue: "SSRF", CWE: "CWE-918"}, Target: "CWE-918", Reason: "仅访问固定域名"}},
+	}
+	path := filepath.Join(t.TempDir(), "airvw.sarif")
+	if err := writeSARIF(path, Config{Profile: ProfileSecurity, Language: "golang"}, result, issues); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got sarifLog
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	run := got.Runs[0]
+	if got.Version != "2.1.0" || len(run.Results) != 5 {
+		t.Fatalf("SARIF结果数 = %d，期望5（未定位的问题不写入）", len(run.Results))
+	}
+	rules := make(map[string]sarifRule)
+	for _, rule := range run.Tool.Driver.Rules {
+		rules[rule.ID] = rule
+	}
+	if rule := rules["CWE-89"]; rule.Properties.SecuritySeverity != "9.0" || rule.HelpURI != "https://cwe.mitre.org/data/definitions/89.html" || len(rule.Properties.Tags) != 3 {
+		t.Errorf("CWE-89规则 = %+v", rule)
+	}
+	if rule := rules["SECRET-ALIYUN-AK"]; rule.Properties.SecuritySeverity != "7.5" {
+		t.Errorf("SECRET-ALIYUN-AK规则 = %+v", rule)
+	}
+	if rule := rules["airvw/suggest"]; rule.Properties.SecuritySeverity != "2.0" {
+		t.Errorf("airvw/suggest规则 = %+v", rule)
+	}
+
+	first, known, suppressed := run.Results[0], run.Results[1], run.Results[4]
+	if first.Level != "error" || first.BaselineState != "new" || first.Locations[0].PhysicalLocation.Region.StartLine != 42 || first.Message.Text != "SQL注入\n使用参数化查询" {
+		t.Errorf("第一个结果 = %+v", first)
+	}
+	if known.Level != "warning" || known.BaselineState != "unchanged" {
+		t.Errorf("存量问题结果 = %+v", known)
+	}
+	if suppressed.RuleID != "CWE-918" || len(suppressed.Suppressions) != 1 || suppressed.Suppressions[0].Kind != "inSource" {
+		t.Errorf("忽略的问题结果 = %+v", suppressed)
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// secretRuleIDPrefix 内置密钥扫描问题的规则ID前缀
+const secretRuleIDPrefix = "SECRET-"
+
+// secretRule 内置密钥扫描规则
+type secretRule struct {
+	ID         string         // 规则ID，用于问题的RuleID和脱敏占位符
+	Name       string         // 中文名称
+	NameEN     string         // 英文名称
+	Pattern    *regexp.Regexp // 匹配规则
+	Group      int            // 密钥所在的捕获组，0表示整个匹配
+	MinEntropy float64        // 密钥的最低香农熵（bit/字符），0表示不校验
+}
+
+// secretRules 内置密钥扫描规则，按顺序匹配，同一位置只报告先命中的规则
+var secretRules = []secretRule{
+	{
+		ID: "ALIYUN-AK", Name: "阿里云AccessKey ID", NameEN: "Aliyun AccessKey ID",
+		Pattern: regexp.MustCompile(`\b(LTAI[0-9A-Za-z]{12,20})\b`), Group: 1,
+	},
+	{
+		ID: "ALIYUN-SK", Name: "阿里云AccessKey Secret", NameEN: "Aliyun AccessKey Secret",
+		Pattern:    regexp.MustCompile(`(?i)(?:access_?key_?secret|secret_?access_?key|aliyun_?secret(?:_?key)?)["']?\s*[:=]{1,2}\s*["']?([0-9A-Za-z]{30})\b`),
+		Group:      1,
+		MinEntropy: 3.5,
+	},
+	{
+		ID: "DINGTALK-TOKEN", Name: "钉钉机器人access_token", NameEN: "DingTalk robot access_token",
+		Pattern: regexp.MustCompile(`(?i)(?:oapi\.dingtalk\.com/robot/send\?access_token=|dingtalk[\w.-]*token["']?\s*[:=]{1,2}\s*["']?)([0-9a-f]{64})\b`), Group: 1,
+	},
+	{
+		ID: "DINGTALK-SECRET", Name: "钉钉机器人加签密钥", NameEN: "DingTalk robot signing secret",
+		Pattern: regexp.MustCompile(`\b(SEC[0-9a-f]{64})\b`), Group: 1,
+	},
+	{
+		ID: "PRIVATE-KEY", Name: "私钥", NameEN: "private key",
+		Pattern: regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )*PRIVATE KEY(?: BLOCK)?-----`),
+	},
+	{
+		ID: "JWT", Name: "JWT令牌", NameEN: "JWT",
+		Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`),
+	},
+	{
+		ID: "URL-PASSWORD", Name: "URL中的密码", NameEN: "password in URL",
+		Pattern: regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^/\s:@"'` + "`" + `]+:([^/\s@"'` + "`" + `]+)@`), Group: 1,
+	},
+	{
+		ID: "GENERIC", Name: "硬编码的密码/密钥", NameEN: "hardcoded password or secret",
+		Pattern:    regexp.MustCompile(`(?i)[\w.-]*(?:password|passwd|pwd|secret|token|api_?key|access_?key|credential)[\w.-]*["']?\s*[:=]{1,2}\s*["']([^"'\s]{8,})["']`),
+		Group:      1,
+		MinEntropy: 3.0,
+	},
+}
+
+// privateKeyBodyPattern 私钥块中的base64内容
+var privateKeyBodyPattern = regexp.MustCompile(`[A-Za-z0-9+/=]{16,}`)
+
+// privateKeyEndPattern 私钥块结束标记
+var privateKeyEndPattern = regexp.MustCompile(`-----END (?:[A-Z]+ )*PRIVATE KEY(?: BLOCK)?-----`)
+
+// secretPlaceholders 常见的示例值/占位符，命中时不视为密钥
+var secretPlaceholders = []string{"example", "changeme", "placeholder", "your_", "your-", "xxxx", "****", "dummy", "redacted"}
+
+// secretMatch 一行中命中的密钥
+type secretMatch struct {
+	rule       secretRule
+	start, end int // 密钥在行中的位置
+}
+
+// scanSecrets 扫描diff中新增行的密钥和凭证，每处命中生成一个block级问题，问题描述中只包含脱敏后的密钥
+func scanSecrets(diffFiles map[string]string, locale string) []BlockIssue {
+	var files []string
+	for file := range diffFiles {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var issues []BlockIssue
+	for _, file := range files {
+		for _, line := range parseDiffLines(diffFiles[file]) {
+			if line.Kind != '+' {
+				continue
+			}
+			for _, m := range findSecrets(line.Text) {
+				name := m.rule.Name
+				if locale == LocaleEN {
+					name = m.rule.NameEN
+				}
+				logDebug("🔑【scanSecrets】%s:%d疑似泄漏%s\n", file, line.NewNo, m.rule.Name)
+				issues = append(issues, BlockIssue{
+					Level:      LevelBlock,
+					File:       file,
+					Line:       strconv.Itoa(line.NewNo),
+					Issue:      tr(locale, "secret_issue", name, maskSecret(line.Text[m.start:m.end])),
+					Suggestion: tr(locale, "secret_suggestion"),
+					RuleID:     secretRuleIDPrefix + m.rule.ID,
+					Secret:     true,
+					Scanner:    ScannerSecret,
+					CWE:        "CWE-798",
+					OWASP:      "A07:2021",
+				})
+			}
+		}
+	}
+	if len(issues) > 0 {
+		logDebug("🔑【scanSecrets】共发现%d处疑似密钥泄漏\n", len(issues))
+	}
+	return issues
+}
+
+// findSecrets 查找一行中的密钥，位置重叠的命中只保留先匹配的规则
+func findSecrets(text string) []secretMatch {
+	var matches []secretMatch
+	for _, rule := range secretRules {
+		for _, loc := range rule.Pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[2*rule.Group], loc[2*rule.Group+1]
+			if start < 0 {
+				continue
+			}
+			value := text[start:end]
+			if isSecretPlaceholder(value) || (rule.MinEntropy > 0 && shannonEntropy(value) < rule.MinEntropy) {
+				continue
+			}
+			overlapped := false
+			for _, m := range matches {
+				if start < m.end && m.start < end {
+					overlapped = true
+					break
+				}
+			}
+			if !overlapped {
+				matches = append(matches, secretMatch{rule: rule, start: start, end: end})
+			}
+		}
+	}
+	return matches
+}
+
+// redactSecrets 将文本中的密钥替换为[REDACTED:规则ID]，私钥块整体脱敏；不区分新增/删除/上下文行，
+// 保证发送给模型的内容中不包含任何密钥，行结构保持不变
+func redactSecrets(text string) string {
+	lines := strings.Split(text, "\n")
+	inPrivateKey := false
+	for i, line := range lines {
+		if inPrivateKey {
+			if privateKeyEndPattern.MatchString(line) {
+				inPrivateKey = false
+				continue
+			}
+			lines[i] = privateKeyBodyPattern.ReplaceAllString(line, "[REDACTED:PRIVATE-KEY]")
+			continue
+		}
+		matches := findSecrets(line)
+		if len(matches) == 0 {
+			continue
+		}
+		var b strings.Builder
+		last := 0
+		privateKey := false
+		sort.Slice(matches, func(a, b int) bool { return matches[a].start < matches[b].start })
+		for _, m := range matches {
+			b.WriteString(line[last:m.start])
+			b.WriteString("[REDACTED:" + m.rule.ID + "]")
+			last = m.end
+			privateKey = privateKey || m.rule.ID == "PRIVATE-KEY"
+		}
+		tail := line[last:]
+		if privateKey {
+			// 私钥可能以「\n」拼接在同一行字符串中，标记之后的内容一并脱敏
+			inPrivateKey = !privateKeyEndPattern.MatchString(tail)
+			tail = privateKeyBodyPattern.ReplaceAllString(tail, "[REDACTED:PRIVATE-KEY]")
+		}
+		b.WriteString(tail)
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isSecretPlaceholder 判断值是否为变量引用、模板占位符或示例值
+func isSecretPlaceholder(value string) bool {
+	if strings.ContainsAny(value[:1], "$%<{") || strings.Contains(value, "${") || strings.Contains(value, "{{") {
+		return true
+	}
+	lower := strings.ToLower(value)
+	for _, placeholder := range secretPlaceholders {
+		if strings.Contains(lower, placeholder) {
+			return true
+		}
+	}
+	return strings.Trim(lower, lower[:1]) == ""
+}
+
+// shannonEntropy 字符串的香农熵（bit/字符）
+func shannonEntropy(value string) float64 {
+	if value == "" {
+		return 0
+	}
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range value {
+		counts[r]++
+		total++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// maskSecret 脱敏展示密钥：较长的密钥保留前4个字符，其余只展示****
+func maskSecret(value string) string {
+	if len(value) < 16 {
+		return "****"
+	}
+	return value[:4] + "****"
+}
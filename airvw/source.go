@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sourceReader 读取变更文件的源码行：优先读取工作区文件，读取失败时回退到diff中新文件一侧的内容
+type sourceReader struct {
+	root      string
+	diffFiles map[string]string
+	cache     map[string]map[int]string
+}
+
+// newSourceReader 创建源码读取器，diffFiles可为nil（仅读取工作区）
+func newSourceReader(root string, diffFiles map[string]string) *sourceReader {
+	return &sourceReader{root: root, diffFiles: diffFiles, cache: make(map[string]map[int]string)}
+}
+
+// Lines 读取并缓存文件内容：行号 -> 行内容
+func (r *sourceReader) Lines(file string) map[int]string {
+	if cached, ok := r.cache[file]; ok {
+		return cached
+	}
+	var lines map[int]string
+	if data, err := os.ReadFile(filepath.Join(r.root, file)); err == nil {
+		lines = make(map[int]string)
+		for i, text := range strings.Split(string(data), "\n") {
+			lines[i+1] = text
+		}
+	} else {
+		logDebug("ℹ️【sourceReader】工作区中不存在%s，使用diff内容\n", file)
+		lines = diffNewLines(r.diffFiles[file])
+	}
+	r.cache[file] = lines
+	return lines
+}
+
+// Line 读取文件指定行，不存在时返回空串
+func (r *sourceReader) Line(file string, lineNo int) string {
+	return r.Lines(file)[lineNo]
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// 副作用类型
+const (
+	EffectComment      = "comment"      // MR/Commit评论
+	EffectNotification = "notification" // 通知
+	EffectReport       = "report"       // 评审报告文件
+)
+
+// SideEffect 评审流程中对外产生的副作用（评论/通知/报告）的执行结果
+type SideEffect struct {
+	Kind     string `json:"kind"`               // 类型：comment/notification/report
+	Target   string `json:"target"`             // 目标：mr/commit/通知渠道名/报告文件路径
+	Success  bool   `json:"success"`            // 是否成功
+	Required bool   `json:"required,omitempty"` // 是否必须成功（失败时终止流程）
+	Attempts int    `json:"attempts"`           // 尝试次数
+	Error    string `json:"error,omitempty"`    // 失败原因
+}
+
+// retryDelay 重试间隔，每次重试翻倍
+var retryDelay = 2 * time.Second
+
+// retrySleep 重试前的等待，测试中替换以免真实等待
+var retrySleep = time.Sleep
+
+// withRetry 执行fn，失败时最多重试retries次，返回实际尝试次数和最后一次错误
+func withRetry(retries int, fn func() error) (int, error) {
+	delay := retryDelay
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(); err == nil || attempt > retries {
+			return attempt, err
+		}
+		logDebug("⚠️【withRetry】第%d次执行失败：%v，%s后重试\n", attempt, err, delay)
+		retrySleep(delay)
+		delay *= 2
+	}
+}
+
+// newSideEffect 根据执行结果构造副作用记录
+func newSideEffect(kind, target string, required bool, attempts int, err error) SideEffect {
+	effect := SideEffect{
+		Kind:     kind,
+		Target:   target,
+		Success:  err == nil,
+		Required: required,
+		Attempts: attempts,
+	}
+	if err != nil {
+		effect.Error = err.Error()
+	}
+	return effect
+}
+
+// writeReport 将评审结果写入JSON报告文件
+func writeReport(path string, result ReviewResult) error {
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("JSON格式化失败：%w", err)
+	}
+	if err := os.WriteFile(path, jsonData, 0644); err != nil {
+		return fmt.Errorf("写入报告文件%s失败：%w", path, err)
+	}
+	return nil
+}
+
+// printSideEffectSummary 输出副作用执行摘要，返回失败的必需副作用数量
+func printSideEffectSummary(effects []SideEffect) int {
+	if len(effects) == 0 {
+		return 0
+	}
+	kindDesc := map[string]string{
+		EffectComment:      "评论",
+		EffectNotification: "通知",
+		EffectReport:       "报告",
+	}
+
+	requiredFailed := 0
+	fmt.Println("\n======= ********** [执行摘要] ********** =======")
+	for _, effect := range effects {
+		required := ""
+		if effect.Required {
+			required = "（必需）"
+		}
+		if effect.Success {
+			fmt.Printf("✅ %s %s%s：成功\n", kindDesc[effect.Kind], effect.Target, required)
+			continue
+		}
+		if effect.Required {
+			requiredFailed++
+		}
+		fmt.Printf("❌ %s %s%s：失败（共尝试%d次）：%s\n", kindDesc[effect.Kind], effect.Target, required, effect.Attempts, effect.Error)
+	}
+	return requiredFailed
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithRetry 测试失败重试：成功后不再重试，重试间隔每次翻倍，超过重试次数后返回最后一次错误
+func TestWithRetry(t *testing.T) {
+	var delays []time.Duration
+	retrySleep = func(d time.Duration) { delays = append(delays, d) }
+	defer func() { retrySleep = time.Sleep }()
+
+	tests := []struct {
+		name         string
+		retries      int
+		failures     int // 前几次执行失败
+		wantAttempts int
+		wantErr      bool
+		wantDelays   []time.Duration
+	}{
+		{"首次成功", 2, 0, 1, false, nil},
+		{"重试后成功", 2, 2, 3, false, []time.Duration{retryDelay, 2 * retryDelay}},
+		{"超过重试次数", 2, 5, 3, true, []time.Duration{retryDelay, 2 * retryDelay}},
+		{"不重试", 0, 5, 1, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delays = nil
+			calls := 0
+			attempts, err := withRetry(tt.retries, func() error {
+				calls++
+				if calls <= tt.failures {
+					return fmt.Errorf("第%d次失败", calls)
+				}
+				return nil
+			})
+			if attempts != tt.wantAttempts || attempts != calls || (err != nil) != tt.wantErr {
+				t.Errorf("withRetry() = %d, %v，执行%d次，期望%d次", attempts, err, calls, tt.wantAttempts)
+			}
+			if tt.wantErr && err.Error() != fmt.Sprintf("第%d次失败", calls) {
+				t.Errorf("应返回最后一次错误，实际%v", err)
+			}
+			if !reflect.DeepEqual(delays, tt.wantDelays) {
+				t.Errorf("重试间隔 = %v，期望%v", delays, tt.wantDelays)
+			}
+		})
+	}
+}
+
+// TestPrintSideEffectSummary 测试执行摘要的输出，只统计失败的必需副作用
+func TestPrintSideEffectSummary(t *testing.T) {
+	effects := []SideEffect{
+		newSideEffect(EffectComment, "mr", false, 1, nil),
+		newSideEffect(EffectNotification, "wecom", false, 3, errors.New("timeout")),
+		newSideEffect(EffectNotification, "backend-ding", true, 3, errors.New("errcode 310000")),
+		newSideEffect(EffectReport, "report.json", true, 1, nil),
+	}
+
+	stdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	failed := printSideEffectSummary(effects)
+	w.Close()
+	os.Stdout = stdout
+	out, _ := io.ReadAll(r)
+
+	if failed != 1 {
+		t.Errorf("printSideEffectSummary() = %d，期望1", failed)
+	}
+	for _, want := range []string{
+		"✅ 评论 mr：成功",
+		"❌ 通知 wecom：失败（共尝试3次）：timeout",
+		"❌ 通知 backend-ding（必需）：失败（共尝试3次）：errcode 310000",
+		"✅ 报告 report.json（必需）：成功",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("执行摘要缺少%q：\n%s", want, out)
+		}
+	}
+	if printSideEffectSummary(nil) != 0 {
+		t.Error("没有副作用时应返回0")
+	}
+}
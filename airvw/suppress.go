@@ -0,0 +1,206 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// suppressDirective 源码中的忽略指令关键字
+const suppressDirective = "airvw:ignore"
+
+// suppressPattern 匹配忽略指令：airvw:ignore <规则ID/等级/分类，逗号分隔> [原因]
+var suppressPattern = regexp.MustCompile(`airvw:ignore\s+(\S+)(?:\s+(.*))?$`)
+
+// commentClosers 块注释结束符，解析原因时去掉
+var commentClosers = []string{"*/", "-->"}
+
+// SuppressedIssue 被airvw:ignore注释忽略的问题
+type SuppressedIssue struct {
+	BlockIssue
+	Target      string `json:"target"`           // 指令中的忽略目标
+	Reason      string `json:"reason,omitempty"` // 忽略原因
+	CommentLine int    `json:"comment_line"`     // 指令所在行号
+}
+
+// suppression 解析后的单条忽略指令
+type suppression struct {
+	targets    []string
+	target     string
+	reason     string
+	line       int
+	standalone bool // 指令独占一行（仅有注释），此时对下一行生效
+}
+
+// Suppressor 按源码中的airvw:ignore注释过滤问题：指令写在问题所在行（行尾注释）或独占问题的上一行时生效
+type Suppressor struct {
+	source *sourceReader
+	cache  map[string]map[int]suppression
+}
+
+// newSuppressor 创建过滤器
+func newSuppressor(source *sourceReader) *Suppressor {
+	return &Suppressor{source: source, cache: make(map[string]map[int]suppression)}
+}
+
+// Filter 过滤问题，返回保留的问题和被忽略的问题；密钥泄漏问题始终参与卡点，不受忽略注释影响
+func (s *Suppressor) Filter(issues []BlockIssue) ([]BlockIssue, []SuppressedIssue) {
+	var kept []BlockIssue
+	var suppressed []SuppressedIssue
+	for _, issue := range issues {
+		if issue.Secret {
+			kept = append(kept, issue)
+			continue
+		}
+		if sup, ok := s.match(issue); ok {
+			logDebug("🔕【Suppressor】%s:%s的问题被第%d行的%s注释忽略（%s）：%s\n",
+				issue.File, issue.Line, sup.line, suppressDirective, sup.target, issue.Issue)
+			suppressed = append(suppressed, SuppressedIssue{BlockIssue: issue, Target: sup.target, Reason: sup.reason, CommentLine: sup.line})
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	return kept, suppressed
+}
+
+// match 查找问题对应的忽略指令
+func (s *Suppressor) match(issue BlockIssue) (suppression, bool) {
+	if issue.File == "unknown" {
+		return suppression{}, false
+	}
+	lineNo, err := strconv.Atoi(issue.Line)
+	if err != nil {
+		return suppression{}, false
+	}
+	directives := s.directives(issue.File)
+	if sup, ok := directives[lineNo]; ok && sup.covers(issue) {
+		return sup, true
+	}
+	if sup, ok := directives[lineNo-1]; ok && sup.standalone && sup.covers(issue) {
+		return sup, true
+	}
+	return suppression{}, false
+}
+
+// covers 判断指令是否覆盖该问题：*/all、规则ID、等级，或问题描述中包含的分类关键字（如SQL注入、并发安全）
+func (sup suppression) covers(issue BlockIssue) bool {
+	for _, target := range sup.targets {
+		switch {
+		case target == "*" || strings.EqualFold(target, "all"):
+			return true
+		case strings.EqualFold(target, issue.Level):
+			return true
+		case matchAnyCategory(issue, []string{target}):
+			return true
+		}
+	}
+	return false
+}
+
+// directives 解析并缓存文件中的忽略指令：行号 -> 指令
+func (s *Suppressor) directives(file string) map[int]suppression {
+	if cached, ok := s.cache[file]; ok {
+		return cached
+	}
+	result := make(map[int]suppression)
+	s.cache[file] = result
+
+	lines := s.source.Lines(file)
+	prefixes := commentPrefixes(file)
+	for lineNo, text := range lines {
+		if !strings.Contains(text, suppressDirective) {
+			continue
+		}
+		if sup, ok := parseSuppression(text, prefixes); ok {
+			sup.line = lineNo
+			result[lineNo] = sup
+		}
+	}
+	return result
+}
+
+// parseSuppression 解析一行中的忽略指令，指令必须位于该语言的注释中（注释起始符不在字符串中）
+func parseSuppression(text string, prefixes []string) (suppression, bool) {
+	idx := strings.Index(text, suppressDirective)
+	if start := commentStart(text, prefixes); start < 0 || start > idx {
+		return suppression{}, false
+	}
+	standalone := false
+	before := strings.TrimSpace(text[:idx])
+	for _, prefix := range prefixes {
+		standalone = standalone || before == prefix
+	}
+
+	directive := strings.TrimSpace(text[idx:])
+	for _, closer := range commentClosers {
+		directive = strings.TrimSpace(strings.TrimSuffix(directive, closer))
+	}
+	matches := suppressPattern.FindStringSubmatch(directive)
+	if matches == nil {
+		return suppression{}, false
+	}
+	sup := suppression{target: matches[1], reason: strings.TrimSpace(matches[2]), standalone: standalone}
+	for _, target := range strings.Split(matches[1], ",") {
+		if target = strings.TrimSpace(target); target != "" {
+			sup.targets = append(sup.targets, target)
+		}
+	}
+	return sup, len(sup.targets) > 0
+}
+
+// commentStart 返回行中第一个不在字符串中的注释起始符的位置，没有注释时返回-1；
+// *只在行首（块注释的续行）时视为注释，避免把乘号、指针当作注释
+func commentStart(text string, prefixes []string) int {
+	trimmed := strings.TrimLeft(text, " \t")
+	for _, prefix := range prefixes {
+		if prefix == "*" && (trimmed == "*" || strings.HasPrefix(trimmed, "* ")) {
+			return len(text) - len(trimmed)
+		}
+	}
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if quote != 0 {
+			switch {
+			case c == '\\' && quote != '`':
+				i++
+			case c == quote:
+				quote = 0
+			}
+			continue
+		}
+		if c == '"' || c == '\'' || c == '`' {
+			quote = c
+			continue
+		}
+		for _, prefix := range prefixes {
+			if prefix != "*" && strings.HasPrefix(text[i:], prefix) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// commentPrefixes 按文件类型返回注释起始符
+func commentPrefixes(file string) []string {
+	ext := strings.ToLower(filepath.Ext(file))
+	switch ext {
+	case ".go", ".java", ".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".swift", ".kt", ".kts",
+		".c", ".h", ".cc", ".cpp", ".hpp", ".rs", ".cs", ".dart", ".scala", ".css", ".scss":
+		return []string{"//", "/*", "*"}
+	case ".py", ".rb", ".sh", ".bash", ".yaml", ".yml", ".toml", ".tf", ".pl", ".r":
+		return []string{"#"}
+	case ".php":
+		return []string{"//", "#", "/*", "*"}
+	case ".sql", ".lua":
+		return []string{"--", "/*"}
+	case ".html", ".xml", ".md", ".vue":
+		return []string{"<!--", "//", "/*"}
+	}
+	if strings.EqualFold(filepath.Base(file), "Dockerfile") {
+		return []string{"#"}
+	}
+	return []string{"//", "#", "--", "/*", "<!--"}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSuppressorFilter 测试行尾/上一行的airvw:ignore注释以及规则ID、等级、分类匹配
+func TestSuppressorFilter(t *testing.T) {
+	root := t.TempDir()
+	source := `package user
+
+func Load(id string) {
+	db.Query("select * from user where id=" + id) // airvw:ignore SQL注入 id已在网关校验
+	// airvw:ignore PAY-001,high 测试账号，非真实卡号
+	log.Printf("card=%s", testCard)
+	panic("unreachable")
+	s := "airvw:ignore all"
+}
+`
+	if err := os.MkdirAll(filepath.Join(root, "user"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "user/load.go"), []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	pyDiff := "@@ -0,0 +1,2 @@\n+import os  # airvw:ignore * 迁移脚本\n+os.system(cmd)\n"
+	suppressor := newSuppressor(newSourceReader(root, map[string]string{"scripts/run.py": pyDiff}))
+
+	lines := []string{
+		"[block] user/load.go:4 - 存在SQL注入风险 - 使用参数化查询",
+		"[block][PAY-001] user/load.go:6 - 日志输出完整卡号 - 脱敏后输出",
+		"[medium] user/load.go:7 - 使用panic终止流程 - 返回error",
+		"[suggest] user/load.go:9 - 字符串常量命名不规范 - 提取常量",
+		"[high] scripts/run.py:2 - 命令注入风险 - 校验cmd",
+		"[high] scripts/run.py:1 - 未使用的导入 - 删除",
+	}
+	kept, suppressed := suppressor.Filter(parseFindings(strings.Join(lines, "\n")))
+	if len(kept) != 3 {
+		t.Fatalf("保留问题数 = %d，期望3：%v", len(kept), kept)
+	}
+	want := []struct {
+		line, target, reason string
+	}{
+		{"4", "SQL注入", "id已在网关校验"},
+		{"6", "PAY-001,high", "测试账号，非真实卡号"},
+		{"1", "*", "迁移脚本"},
+	}
+	if len(suppressed) != len(want) {
+		t.Fatalf("忽略问题数 = %d，期望%d", len(suppressed), len(want))
+	}
+	for i, w := range want {
+		got := suppressed[i]
+		if got.Line != w.line || got.Target != w.target || got.Reason != w.reason {
+			t.Errorf("suppressed[%d] = {%s %s %s}，期望%v", i, got.Line, got.Target, got.Reason, w)
+		}
+	}
+}
+
+// TestSuppressorKeepsSecrets 测试密钥泄漏问题不受airvw:ignore注释影响
+func TestSuppressorKeepsSecrets(t *testing.T) {
+	diff := "@@ -0,0 +1,1 @@\n+const ak = \"LTAI5tQ9xXbK2mZ8pR7vW3nJ\" // airvw:ignore * 测试账号\n"
+	suppressor := newSuppressor(newSourceReader(t.TempDir(), map[string]string{"conf/aliyun.go": diff}))
+	issues := []BlockIssue{
+		{Level: LevelBlock, File: "conf/aliyun.go", Line: "1", RuleID: "SECRET-ALIYUN-AK", Secret: true},
+		{Level: LevelMedium, File: "conf/aliyun.go", Line: "1", Issue: "常量命名不规范"},
+	}
+	kept, suppressed := suppressor.Filter(issues)
+	if len(kept) != 1 || !kept[0].Secret {
+		t.Errorf("保留问题 = %v，期望只保留密钥泄漏问题", kept)
+	}
+	if len(suppressed) != 1 || suppressed[0].Secret {
+		t.Errorf("忽略问题 = %v，期望只忽略模型问题", suppressed)
+	}
+}
+
+// TestParseSuppressionCommentToken 测试指令只在真正的注释中生效：乘号、字符串中的注释符不视为注释
+func TestParseSuppressionCommentToken(t *testing.T) {
+	tests := []struct {
+		file, text string
+		want       bool
+		standalone bool
+	}{
+		{"a.go", `	// airvw:ignore all`, true, true},
+		{"a.go", `	x := f() /* airvw:ignore high 原因 */`, true, false},
+		{"a.go", `	 * airvw:ignore PAY-001`, true, true},
+		{"a.go", `	url := "http://x" // airvw:ignore all`, true, false},
+		{"a.go", `	total := price * qty + len("airvw:ignore all")`, false, false},
+		{"a.go", `	s := "// airvw:ignore all"`, false, false},
+		{"a.go", "\tq := `# airvw:ignore all`", false, false},
+		{"run.py", `msg = "# airvw:ignore all"`, false, false},
+		{"run.py", `msg = 'it\'s # airvw:ignore all'`, false, false},
+		{"run.py", `os.system(cmd)  # airvw:ignore *`, true, false},
+	}
+	for _, tt := range tests {
+		sup, ok := parseSuppression(tt.text, commentPrefixes(tt.file))
+		if ok != tt.want || sup.standalone != tt.standalone {
+			t.Errorf("parseSuppression(%q) = %v（standalone=%v），期望%v（standalone=%v）", tt.text, ok, sup.standalone, tt.want, tt.standalone)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// 模板名称
+const (
+	TemplateNotify        = "notify"         // 通知正文
+	TemplateCommentMR     = "comment_mr"     // MR评论正文
+	TemplateCommentCommit = "comment_commit" // Commit评论正文
+)
+
+// 支持的语言区域
+const (
+	LocaleZH = "zh"
+	LocaleEN = "en"
+)
+
+//go:embed templates
+var builtinTemplates embed.FS
+
+// TemplateConfig 模板配置
+type TemplateConfig struct {
+	Dir   string            `json:"dir"`   // 模板覆盖目录，存在<模板名>.md.tmpl时优先使用
+	Files map[string]string `json:"files"` // 单个模板覆盖文件：模板名 -> 文件路径（优先级高于dir）
+}
+
+// TemplateData 模板渲染数据
+type TemplateData struct {
+	Result     ReviewResult // 完整评审结果
+	Issues     []BlockIssue // 需展示的问题（已按MaxIssues截断）
+	Findings   []BlockIssue // 本次评审的全部问题（含未参与卡点的问题），按等级排序
+	Omitted    int          // 因MaxIssues限制未展示的问题数
+	RawResult  string       // AI原始评审输出
+	Language   string       // 评审语言展示名（如Go、Java）
+	MRID       int
+	CommitID   string
+	FromCommit string
+	ToCommit   string
+	Levels     struct{ Block, High, Medium, Suggest string }
+}
+
+// messages 代码中拼接的文案，按语言区域区分
+var messages = map[string]map[string]string{
+	LocaleZH: {
+		"title":             "AI代码审查结果通知",
+		"policy_violation":  "%s级问题%d个（允许%d个）",
+		"list_separator":    "；",
+		"msg_blocked":       "检测到%d个问题超出卡点阈值：%s，终止流程",
+		"msg_advisory":      "advisory模式：检测到%d个问题超出卡点阈值：%s，仅报告不终止流程",
+		"msg_passed_issues": "评审通过，发现%d个非阻塞问题",
+		"secret_issue":      "疑似泄漏%s：%s",
+		"secret_suggestion": "立即吊销并轮换该凭证，改为从环境变量或密钥管理服务读取；已推送的提交需清理Git历史",
+
+		"dependency_vuln_issue":         "依赖%s@%s存在已知漏洞%s：%s",
+		"dependency_vuln_fixed":         "升级到%s或更高版本",
+		"dependency_vuln_unfixed":       "该漏洞暂无修复版本，评估影响后更换依赖或采取缓解措施",
+		"dependency_license_issue":      "依赖%s@%s的许可证%s不在允许列表中",
+		"dependency_license_suggestion": "更换为许可证合规的依赖，或经法务评估后加入dependencies.license_allowlist",
+
+		"test_gap_uncovered":            "新增代码第%s行未被测试覆盖",
+		"test_gap_uncovered_suggestion": "补充覆盖这些新增逻辑（含异常分支）的测试用例",
+		"test_gap_stale":                "修改了生产代码，但未更新对应的测试文件%s",
+		"test_gap_missing":              "修改了生产代码，但没有对应的测试文件（如%s）",
+		"test_gap_suggestion":           "为本次新增/修改的逻辑补充单元测试，覆盖正常流程和异常分支",
+	},
+	LocaleEN: {
+		"title":             "AI Code Review Notification",
+		"policy_violation":  "%s: %d issue(s) (max %d)",
+		"list_separator":    "; ",
+		"msg_blocked":       "Found %d issue(s) exceeding the gating thresholds: %s, pipeline blocked",
+		"msg_advisory":      "Advisory mode: %d issue(s) exceed the gating thresholds: %s, reported only",
+		"msg_passed_issues": "Review passed with %d non-blocking issue(s)",
+		"secret_issue":      "Possible leaked %s: %s",
+		"secret_suggestion": "Revoke and rotate the credential immediately, load it from environment variables or a secrets manager instead, and purge it from the Git history if already pushed",
+
+		"dependency_vuln_issue":         "Dependency %s@%s has known vulnerability %s: %s",
+		"dependency_vuln_fixed":         "Upgrade to %s or later",
+		"dependency_vuln_unfixed":       "No fixed version is available yet; assess the impact and replace the dependency or apply mitigations",
+		"dependency_license_issue":      "License %[3]s of dependency %[1]s@%[2]s is not in the allowlist",
+		"dependency_license_suggestion": "Use a dependency with a compliant license, or add the license to dependencies.license_allowlist after legal review",
+
+		"test_gap_uncovered":            "Added lines %s are not covered by tests",
+		"test_gap_uncovered_suggestion": "Add test cases covering the new logic, including error paths",
+		"test_gap_stale":                "Production code changed but the corresponding test file %s was not updated",
+		"test_gap_missing":              "Production code changed without a corresponding test file (e.g. %s)",
+		"test_gap_suggestion":           "Add unit tests for the new or changed logic, covering both normal and error paths",
+	},
+}
+
+// tr 获取指定语言区域的文案，未知区域回退到中文
+func tr(locale, key string, args ...interface{}) string {
+	catalog, ok := messages[locale]
+	if !ok {
+		catalog = messages[LocaleZH]
+	}
+	if len(args) == 0 {
+		return catalog[key]
+	}
+	return fmt.Sprintf(catalog[key], args...)
+}
+
+// TemplateRenderer 评论/通知模板渲染器：覆盖文件 > 覆盖目录 > 内置模板
+type TemplateRenderer struct {
+	locale string
+	conf   TemplateConfig
+	cache  map[string]*template.Template
+}
+
+// newTemplateRenderer 创建模板渲染器
+func newTemplateRenderer(locale string, conf TemplateConfig) *TemplateRenderer {
+	if _, ok := messages[locale]; !ok {
+		logDebug("⚠️【TemplateRenderer】不支持的语言区域%s，使用默认中文模板\n", locale)
+		locale = LocaleZH
+	}
+	return &TemplateRenderer{
+		locale: locale,
+		conf:   conf,
+		cache:  make(map[string]*template.Template),
+	}
+}
+
+// Locale 当前语言区域
+func (r *TemplateRenderer) Locale() string {
+	return r.locale
+}
+
+// NewData 基于评审结果和全部问题构建模板数据，maxIssues>0时截断展示的问题
+func (r *TemplateRenderer) NewData(config Config, result ReviewResult, findings []BlockIssue, rawResult string, maxIssues int) TemplateData {
+	data := TemplateData{
+		Result:     result,
+		Issues:     result.BlockIssues,
+		Findings:   findings,
+		RawResult:  rawResult,
+		Language:   languageDisplayName(config.Language),
+		MRID:       config.MRID,
+		CommitID:   config.CommitID,
+		FromCommit: config.FromCommit,
+		ToCommit:   config.ToCommit,
+	}
+	if maxIssues > 0 && len(data.Issues) > maxIssues {
+		data.Omitted = len(data.Issues) - maxIssues
+		data.Issues = data.Issues[:maxIssues]
+	}
+	data.Levels.Block, data.Levels.High, data.Levels.Medium, data.Levels.Suggest = LevelBlock, LevelHigh, LevelMedium, LevelSuggest
+	return data
+}
+
+// Render 渲染指定名称的模板
+func (r *TemplateRenderer) Render(name string, data TemplateData) (string, error) {
+	tmpl, err := r.load(name)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("渲染模板%s失败：%w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// load 按优先级加载模板并缓存
+func (r *TemplateRenderer) load(name string) (*template.Template, error) {
+	if tmpl, ok := r.cache[name]; ok {
+		return tmpl, nil
+	}
+
+	fileName := name + ".md.tmpl"
+	var text []byte
+	var err error
+	source := ""
+	if file, ok := r.conf.Files[name]; ok && file != "" {
+		source = file
+		text, err = os.ReadFile(file)
+	} else if candidate := filepath.Join(r.conf.Dir, fileName); r.conf.Dir != "" && fileExists(candidate) {
+		source = candidate
+		text, err = os.ReadFile(candidate)
+	} else {
+		source = "内置模板templates/" + r.locale + "/" + fileName
+		text, err = builtinTemplates.ReadFile("templates/" + r.locale + "/" + fileName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取模板%s失败：%w", source, err)
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("解析模板%s失败：%w", source, err)
+	}
+	logDebug("ℹ️【TemplateRenderer】模板%s来源：%s\n", name, source)
+	r.cache[name] = tmpl
+	return tmpl, nil
+}
+
+// templateFuncs 模板可用的辅助函数
+var templateFuncs = template.FuncMap{
+	"add":   func(a, b int) int { return a + b },
+	"json":  toJSON,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// fileExists 判断文件是否存在
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRenderTemplates 测试内置中英文模板渲染评论和通知正文，以及问题数量截断和覆盖文件
+func TestRenderTemplates(t *testing.T) {
+	issues := []BlockIssue{
+		{Level: LevelBlock, File: "dao/user.go", Line: "12", Issue: "SQL拼接", Suggestion: "使用参数化查询", RuleID: "SEC-001"},
+		{Level: LevelMedium, File: "service/user.go", Line: "30", Issue: "未处理错误", Known: true},
+	}
+	result := ReviewResult{Status: "blocked", TotalIssues: 2, Message: "block", BlockIssues: issues, Model: "qwen3-coder-plus"}
+	config := Config{Language: "golang", MRID: 42, CommitID: "abc123", FromCommit: "a1", ToCommit: "b2"}
+
+	tests := []struct {
+		locale string
+		name   string
+		want   []string
+	}{
+		{LocaleZH, TemplateNotify, []string{"## AI代码审查结果通知", "### ❌ 评审被阻断", "**1. [block][SEC-001] dao/user.go:12**", "- 修复建议: 使用参数化查询", "（存量问题）", "- **模型**: qwen3-coder-plus"}},
+		{LocaleEN, TemplateNotify, []string{"## AI Code Review Notification", "### ❌ Review blocked", "**1. [block][SEC-001] dao/user.go:12**", "- Suggestion: 使用参数化查询", "(known, baselined)"}},
+		{LocaleZH, TemplateCommentMR, []string{"AI Code Review 结果（MR #42）", "提交ID a1 → b2 变更的Go文件", "共发现2个问题", "- [block][SEC-001] dao/user.go:12 - SQL拼接 - 使用参数化查询"}},
+		{LocaleEN, TemplateCommentCommit, []string{"AI Code Review (Commit abc123)", "Go files changed between a1 → b2", "2 issue(s) found", "- [medium] service/user.go:30 - 未处理错误 (known, baselined)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.name, func(t *testing.T) {
+			renderer := newTemplateRenderer(tt.locale, TemplateConfig{})
+			got, err := renderer.Render(tt.name, renderer.NewData(config, result, issues, "", 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("渲染结果缺少%q：\n%s", want, got)
+				}
+			}
+		})
+	}
+
+	t.Run("截断展示的问题", func(t *testing.T) {
+		renderer := newTemplateRenderer(LocaleZH, TemplateConfig{})
+		got, err := renderer.Render(TemplateNotify, renderer.NewData(config, result, issues, "", 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(got, "仅显示前1个问题（共2个）") || strings.Contains(got, "service/user.go") {
+			t.Errorf("截断后的渲染结果不正确：\n%s", got)
+		}
+	})
+
+	t.Run("未知语言区域回退到中文", func(t *testing.T) {
+		if renderer := newTemplateRenderer("fr", TemplateConfig{}); renderer.Locale() != LocaleZH {
+			t.Errorf("Locale() = %s，期望%s", renderer.Locale(), LocaleZH)
+		}
+	})
+
+	t.Run("覆盖文件优先于内置模板", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "notify.md.tmpl")
+		if err := os.WriteFile(file, []byte("{{.Result.Status}}: {{len .Issues}}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		renderer := newTemplateRenderer(LocaleEN, TemplateConfig{Files: map[string]string{TemplateNotify: file}})
+		got, err := renderer.Render(TemplateNotify, renderer.NewData(config, result, issues, "", 0))
+		if err != nil || got != "blocked: 2" {
+			t.Errorf("Render() = %q, %v，期望blocked: 2", got, err)
+		}
+	})
+}
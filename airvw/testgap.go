@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// testGapRuleID 测试缺口问题的规则ID，可在卡点策略overrides.categories和airvw:ignore中使用
+const testGapRuleID = "TEST-GAP"
+
+// defaultTestGapMinLines 默认的最少有效新增行数，少于该值的改动（如修改常量、日志）不要求测试
+const defaultTestGapMinLines = 3
+
+// TestGapConfig 测试缺口检查配置：生产代码的变更是否有对应的测试变更，或新增行是否被测试覆盖
+type TestGapConfig struct {
+	Enabled       bool     `json:"enabled"`         // 是否检查，指定--coverage-file时自动开启
+	Level         string   `json:"level"`           // 问题等级，默认suggest
+	CoverageFile  string   `json:"coverage_file"`   // 覆盖率文件：Go coverprofile、JaCoCo XML或lcov，--coverage-file优先
+	ExcludePaths  []string `json:"exclude_paths"`   // 不要求测试的路径（glob），如main.go、配置和DTO
+	MinAddedLines int      `json:"min_added_lines"` // 有效新增行（不含空行和注释）少于该值的文件不检查，默认3
+}
+
+// testCommentPrefixes 注释行的常见前缀，不计入有效新增行
+var testCommentPrefixes = []string{"//", "#", "/*", "* ", "*/", "--", "<!--"}
+
+// validateTestGap 校验测试缺口检查配置
+func validateTestGap(conf TestGapConfig) error {
+	if conf.Level != "" && levelRank(conf.Level) < 0 {
+		return fmt.Errorf("test_gap.level无效：%s（可选：block/high/medium/suggest）", conf.Level)
+	}
+	if conf.MinAddedLines < 0 {
+		return fmt.Errorf("test_gap.min_added_lines不能为负数")
+	}
+	return nil
+}
+
+// testFilePattern 测试文件命名规则编译后的正则
+type testFilePattern struct {
+	template string // 原始规则，如{name}_test.go
+	pattern  *regexp.Regexp
+}
+
+// compileTestFiles 编译语言声明中的测试文件命名规则
+func compileTestFiles(templates []string) []testFilePattern {
+	var patterns []testFilePattern
+	for _, t := range templates {
+		expr := strings.ReplaceAll(regexp.QuoteMeta(t), regexp.QuoteMeta("{name}"), `([^/]+)`)
+		re, err := regexp.Compile(`(?:^|/)` + expr + `$`)
+		if err != nil {
+			logDebug("⚠️【compileTestFiles】无效的测试文件规则：%s，%v\n", t, err)
+			continue
+		}
+		patterns = append(patterns, testFilePattern{template: t, pattern: re})
+	}
+	return patterns
+}
+
+// isTestFile 判断文件是否符合任一测试文件命名规则
+func isTestFile(patterns []testFilePattern, file string) bool {
+	for _, p := range patterns {
+		if p.pattern.MatchString(file) {
+			return true
+		}
+	}
+	return false
+}
+
+// testCandidates 被测文件按命名规则对应的测试文件路径：同目录，以及Maven/Gradle约定的src/test目录
+func testCandidates(patterns []testFilePattern, file string) []string {
+	dir, base := path.Split(file)
+	name := strings.TrimSuffix(base, path.Ext(base))
+	dirs := []string{dir}
+	if strings.Contains(dir, "src/main/") {
+		dirs = append(dirs, strings.Replace(dir, "src/main/", "src/test/", 1))
+	}
+	var candidates []string
+	for _, d := range dirs {
+		for _, p := range patterns {
+			candidates = append(candidates, d+strings.ReplaceAll(p.template, "{name}", name))
+		}
+	}
+	return candidates
+}
+
+// findTestGaps 检查变更的生产代码是否经过测试：覆盖率文件中有该文件的数据时报告未被覆盖的新增行，
+// 否则按语言的测试文件命名规则检查本次是否同时修改了对应的测试文件
+func findTestGaps(conf TestGapConfig, process ReviewProcess, repoPath string, diffFiles map[string]string, locale string) ([]BlockIssue, error) {
+	spec, ok := process.(*LanguageSpec)
+	if !conf.Enabled || !ok || (len(spec.TestFiles) == 0 && conf.CoverageFile == "") {
+		return nil, nil
+	}
+	var coverage *CoverageProfile
+	if conf.CoverageFile != "" {
+		var err error
+		if coverage, err = loadCoverage(conf.CoverageFile); err != nil {
+			return nil, err
+		}
+	}
+	level := firstNonEmpty(conf.Level, LevelSuggest)
+	minLines := conf.MinAddedLines
+	if minLines == 0 {
+		minLines = defaultTestGapMinLines
+	}
+
+	patterns := compileTestFiles(spec.TestFiles)
+	var files []string
+	for file := range diffFiles {
+		if !isTestFile(patterns, file) {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+
+	var issues []BlockIssue
+	for _, file := range files {
+		if matchAnyGlob(conf.ExcludePaths, file) {
+			continue
+		}
+		added := effectiveAddedLines(diffFiles[file])
+		if len(added) < minLines {
+			continue
+		}
+
+		issue := BlockIssue{Level: level, File: file, RuleID: testGapRuleID, Scanner: ScannerTestGap}
+		if lines, ok := coverage.Lookup(file); ok {
+			var uncovered []int
+			for _, n := range added {
+				if covered, executable := lines[n]; executable && !covered {
+					uncovered = append(uncovered, n)
+				}
+			}
+			if len(uncovered) == 0 {
+				continue
+			}
+			issue.Line = strconv.Itoa(uncovered[0])
+			issue.Issue = tr(locale, "test_gap_uncovered", formatLineRanges(uncovered))
+			issue.Suggestion = tr(locale, "test_gap_uncovered_suggestion")
+		} else {
+			// 没有覆盖率数据时按命名规则配对，未声明测试文件规则的语言（如Rust的单元测试写在源文件中）不检查；
+			// 按目录配对，其他包中的同名测试文件不算
+			if len(patterns) == 0 {
+				continue
+			}
+			candidates := testCandidates(patterns, file)
+			if changedAny(diffFiles, candidates) {
+				continue
+			}
+			issue.Line = strconv.Itoa(added[0])
+			issue.Issue = tr(locale, "test_gap_missing", candidates[0])
+			for _, candidate := range candidates {
+				if _, err := os.Stat(path.Join(repoPath, candidate)); err == nil {
+					issue.Issue = tr(locale, "test_gap_stale", candidate)
+					break
+				}
+			}
+			issue.Suggestion = tr(locale, "test_gap_suggestion")
+		}
+		logDebug("🧪【findTestGaps】%s：%s\n", file, issue.Issue)
+		issues = append(issues, issue)
+	}
+	if len(issues) > 0 {
+		logDebug("🧪【findTestGaps】共%d个文件的变更缺少测试\n", len(issues))
+	}
+	return issues, nil
+}
+
+// changedAny 判断候选文件中是否有本次变更的文件
+func changedAny(diffFiles map[string]string, candidates []string) bool {
+	for _, candidate := range candidates {
+		if _, ok := diffFiles[candidate]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// effectiveAddedLines diff中新增的有效代码行号，不含空行和注释行
+func effectiveAddedLines(diff string) []int {
+	var lines []int
+	for _, line := range parseDiffLines(diff) {
+		text := strings.TrimSpace(line.Text)
+		if line.Kind != '+' || text == "" || text == "*" || hasAnyPrefix(text, testCommentPrefixes) {
+			continue
+		}
+		lines = append(lines, line.NewNo)
+	}
+	return lines
+}
+
+// hasAnyPrefix 判断字符串是否以任一前缀开头
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatLineRanges 将有序行号合并为区间，如12-15, 20
+func formatLineRanges(lines []int) string {
+	var ranges []string
+	for i := 0; i < len(lines); {
+		j := i
+		for j+1 < len(lines) && lines[j+1] == lines[j]+1 {
+			j++
+		}
+		if i == j {
+			ranges = append(ranges, strconv.Itoa(lines[i]))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", lines[i], lines[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(ranges, ", ")
+}
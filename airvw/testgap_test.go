@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFindTestGaps 测试按命名规则配对测试文件：同时修改了测试的文件不报告，已有测试未更新和缺少测试分别提示，
+// 新增行过少和排除路径中的文件不检查
+func TestFindTestGaps(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "service"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "service/refund_test.go"), []byte("package service\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	code := "@@ -1,2 +1,6 @@\n package service\n \n+// Pay 支付\n+func Pay(amount int) error {\n+\tif amount <= 0 {\n+\t\treturn errInvalidAmount\n+\t}\n+\treturn nil\n+}\n"
+	diffFiles := map[string]string{
+		"service/order.go":      code,
+		"service/order_test.go": "@@ -1,1 +1,2 @@\n package service\n+func TestPay(t *testing.T) {}\n",
+		"service/refund.go":     code,
+		"service/coupon.go":     code,
+		"service/const.go":      "@@ -1,2 +1,3 @@\n package service\n+const maxRetry = 3\n",
+		"cmd/server/main.go":    code,
+	}
+	conf := TestGapConfig{Enabled: true, Level: LevelMedium, ExcludePaths: []string{"cmd/**"}}
+	issues, err := findTestGaps(conf, GetReviewProcess("golang"), root, diffFiles, LocaleZH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]BlockIssue)
+	for _, issue := range issues {
+		got[issue.File] = issue
+	}
+	if len(got) != 2 {
+		t.Fatalf("findTestGaps() = %+v，期望coupon.go和refund.go两个问题", issues)
+	}
+	if issue := got["service/refund.go"]; !strings.Contains(issue.Issue, "未更新对应的测试文件service/refund_test.go") || issue.Line != "4" || issue.Level != LevelMedium || issue.RuleID != testGapRuleID {
+		t.Errorf("refund.go = %+v", issue)
+	}
+	if issue := got["service/coupon.go"]; !strings.Contains(issue.Issue, "没有对应的测试文件（如service/coupon_test.go）") {
+		t.Errorf("coupon.go = %+v", issue)
+	}
+
+	if issues, _ := findTestGaps(TestGapConfig{}, GetReviewProcess("golang"), root, diffFiles, LocaleZH); len(issues) != 0 {
+		t.Errorf("未开启时不应检查：%+v", issues)
+	}
+}
+
+// TestFindTestGapsCoverage 测试有覆盖率数据时按新增行是否被覆盖判断，与是否修改测试文件无关
+func TestFindTestGapsCoverage(t *testing.T) {
+	coverage := filepath.Join(t.TempDir(), "lcov.info")
+	content := "SF:src/price.ts\nDA:2,1\nDA:3,0\nDA:4,0\nDA:6,1\nend_of_record\nSF:src/cart.ts\nDA:2,1\nDA:3,1\nDA:4,1\nend_of_record\n"
+	if err := os.WriteFile(coverage, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	code := "@@ -1,1 +1,6 @@\n export const a = 1\n+export function f(x: number) {\n+  if (x < 0) {\n+    return 0\n+  }\n+  return x\n"
+	diffFiles := map[string]string{
+		"src/price.ts":      code,
+		"src/price.spec.ts": "@@ -1,1 +1,2 @@\n import { f } from './price'\n+it('f', () => expect(f(1)).toBe(1))\n",
+		"src/cart.ts":       code,
+	}
+	issues, err := findTestGaps(TestGapConfig{Enabled: true, CoverageFile: coverage}, GetReviewProcess("typescript"), ".", diffFiles, LocaleEN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].File != "src/price.ts" || issues[0].Line != "3" || issues[0].Level != LevelSuggest || !strings.Contains(issues[0].Issue, "3-4") {
+		t.Errorf("findTestGaps() = %+v，期望price.ts第3-4行未覆盖", issues)
+	}
+}
+
+// TestFindTestGapsPairsByDirectory 测试按目录配对：一个包中的同名测试文件不能覆盖其他包的源文件，
+// Maven工程src/main对应src/test下的测试
+func TestFindTestGapsPairsByDirectory(t *testing.T) {
+	code := "@@ -1,1 +1,4 @@\n package x\n+func A() int {\n+\treturn 1\n+}\n"
+	diffFiles := map[string]string{
+		"order/handler.go":      code,
+		"order/handler_test.go": "@@ -1,1 +1,2 @@\n package order\n+func TestA(t *testing.T) {}\n",
+		"user/handler.go":       code,
+	}
+	issues, err := findTestGaps(TestGapConfig{Enabled: true}, GetReviewProcess("golang"), t.TempDir(), diffFiles, LocaleZH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].File != "user/handler.go" {
+		t.Errorf("findTestGaps() = %+v，期望只有user/handler.go缺少测试", issues)
+	}
+
+	java := "@@ -1,1 +1,4 @@\n package com.example;\n+public class Order {\n+    int id;\n+}\n"
+	diffFiles = map[string]string{
+		"src/main/java/com/example/Order.java":     java,
+		"src/test/java/com/example/OrderTest.java": "@@ -1,1 +1,2 @@\n package com.example;\n+class OrderTest {}\n",
+		"src/main/java/com/example/api/Order.java": java,
+	}
+	issues, err = findTestGaps(TestGapConfig{Enabled: true}, GetReviewProcess("java"), t.TempDir(), diffFiles, LocaleZH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues) != 1 || issues[0].File != "src/main/java/com/example/api/Order.java" {
+		t.Errorf("findTestGaps() = %+v，期望只有api/Order.java缺少测试", issues)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// tscDiagnosticPattern 匹配tsc --pretty false的诊断输出：文件(行,列): error TS2322: 描述
+var tscDiagnosticPattern = regexp.MustCompile(`^(.+?)\((\d+),(\d+)\):\s*(error|warning)\s+(TS\d+):\s*(.*)$`)
+
+// runTypeScriptLint TypeScript的静态检查：执行tsc --noEmit类型检查和eslint，优先使用仓库node_modules中的版本，eslint使用仓库自身的配置；
+// 返回文件路径 -> 检查结果段落
+func runTypeScriptLint(repoPath string, diffFiles map[string]string) map[string][]string {
+	logDebugln("\n=====================================")
+	logDebugln("【RunTypeScriptLint】开始执行")
+	logDebug("  - 仓库路径：%s\n", repoPath)
+	logDebug("  - 待检查文件数：%d\n", len(diffFiles))
+	logDebugln("=====================================")
+
+	diagnostics := runTypeCheck(repoPath, diffFiles)
+	eslint := findTool(repoPath, "eslint")
+
+	lintSections := make(map[string][]string)
+	for file := range diffFiles {
+		var sections []string
+		if diagnostics != nil {
+			if len(diagnostics[file]) > 0 {
+				sections = append(sections, "类型检查：\n"+strings.Join(diagnostics[file], "\n"))
+			}
+		} else {
+			sections = append(sections, "类型检查未执行：缺少tsc环境或tsconfig.json")
+		}
+
+		if eslint == "" {
+			sections = append(sections, "eslint未执行：缺少eslint环境")
+		} else {
+			logDebug("ℹ️【RunTypeScriptLint】eslint检查文件：%s\n", file)
+			cmd := exec.Command(eslint, "--format", "unix", "--no-error-on-unmatched-pattern", file)
+			cmd.Dir = repoPath
+			output, err := cmd.CombinedOutput()
+			// eslint发现问题时退出码为1，退出码2表示配置或执行错误
+			if exitErr, ok := err.(*exec.ExitError); err != nil && (!ok || exitErr.ExitCode() != 1) {
+				logDebug("⚠️【RunTypeScriptLint】文件%s eslint执行失败：%v\n", file, err)
+				sections = append(sections, fmt.Sprintf("eslint执行失败：%s，输出：%s", err.Error(), string(output)))
+			} else if out := strings.TrimSpace(string(output)); out != "" {
+				sections = append(sections, "eslint：\n"+out)
+			}
+		}
+		lintSections[file] = sections
+	}
+	return lintSections
+}
+
+// runTypeCheck 对整个工程执行一次tsc --noEmit（包含.vue文件且安装了vue-tsc时使用vue-tsc），按变更文件归类诊断信息；
+// 缺少tsc或tsconfig.json时返回nil
+func runTypeCheck(repoPath string, diffFiles map[string]string) map[string][]string {
+	if !fileExists(filepath.Join(repoPath, "tsconfig.json")) {
+		logDebugln("⚠️【RunTypeScriptLint】未找到tsconfig.json，跳过类型检查")
+		return nil
+	}
+	tsc := findTool(repoPath, "tsc")
+	for file := range diffFiles {
+		if strings.HasSuffix(file, ".vue") {
+			if vueTsc := findTool(repoPath, "vue-tsc"); vueTsc != "" {
+				tsc = vueTsc
+			}
+			break
+		}
+	}
+	if tsc == "" {
+		logDebugln("⚠️【RunTypeScriptLint】未检测到tsc，跳过类型检查")
+		return nil
+	}
+
+	logDebug("ℹ️【RunTypeScriptLint】执行类型检查：%s --noEmit\n", tsc)
+	cmd := exec.Command(tsc, "--noEmit", "--pretty", "false", "-p", "tsconfig.json")
+	cmd.Dir = repoPath
+	// 存在类型错误时tsc以非0退出，只关心输出内容
+	output, _ := cmd.CombinedOutput()
+	return parseTscDiagnostics(string(output), diffFiles)
+}
+
+// parseTscDiagnostics 解析tsc诊断输出，只保留变更文件的诊断：文件路径 -> 「行:列 TS错误码 描述」列表
+func parseTscDiagnostics(output string, diffFiles map[string]string) map[string][]string {
+	diagnostics := make(map[string][]string)
+	for _, line := range strings.Split(output, "\n") {
+		matches := tscDiagnosticPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if matches == nil {
+			continue
+		}
+		file := filepath.ToSlash(filepath.Clean(matches[1]))
+		if _, ok := diffFiles[file]; !ok {
+			continue
+		}
+		diagnostics[file] = append(diagnostics[file], fmt.Sprintf("%s:%s %s %s", matches[2], matches[3], matches[5], matches[6]))
+	}
+	return diagnostics
+}
+
+// findTool 查找可执行文件：优先使用仓库node_modules/.bin中的版本，其次使用PATH中的版本，都不存在时返回空串
+func findTool(repoPath, name string) string {
+	local := filepath.Join(repoPath, "node_modules", ".bin", name)
+	if fileExists(local) {
+		if absPath, err := filepath.Abs(local); err == nil {
+			return absPath
+		}
+		return local
+	}
+	if path, err := exec.LookPath(name); err == nil {
+		return path
+	}
+	return ""
+}
+
+// hasAnySuffix 判断路径是否以任一后缀结尾
+func hasAnySuffix(path string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
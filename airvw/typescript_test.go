@@ -0,0 +1,42 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseTscDiagnostics 测试tsc诊断输出的解析，只保留变更文件的诊断
+func TestParseTscDiagnostics(t *testing.T) {
+	output := `src/api/user.ts(12,7): error TS2322: Type 'string' is not assignable to type 'number'.
+src/api/user.ts(30,1): error TS7006: Parameter 'req' implicitly has an 'any' type.
+src/legacy/old.ts(3,1): error TS2304: Cannot find name 'foo'.
+Found 3 errors in 2 files.`
+	diffFiles := map[string]string{"src/api/user.ts": "", "src/views/Home.vue": ""}
+
+	want := map[string][]string{
+		"src/api/user.ts": {
+			"12:7 TS2322 Type 'string' is not assignable to type 'number'.",
+			"30:1 TS7006 Parameter 'req' implicitly has an 'any' type.",
+		},
+	}
+	if got := parseTscDiagnostics(output, diffFiles); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTscDiagnostics() = %v，期望%v", got, want)
+	}
+}
+
+// TestTypeScriptFilterFiles 测试TypeScript与JavaScript评审流程的文件划分
+func TestTypeScriptFilterFiles(t *testing.T) {
+	items := []DiffItem{
+		{NewPath: "a.ts", Diff: "+"}, {NewPath: "b.tsx", Diff: "+"}, {NewPath: "c.mts", Diff: "+"},
+		{NewPath: "d.vue", Diff: "+"}, {NewPath: "e.js", Diff: "+"}, {NewPath: "f.cjs", Diff: "+"},
+	}
+	if name := GetReviewProcess("ts").GetPromptName(); name != "typescript" {
+		t.Fatalf("GetReviewProcess(ts)的prompt模板 = %s，期望typescript", name)
+	}
+	if got := GetReviewProcess("typescript").FilterFiles(items); len(got) != 4 || got["e.js"] != "" {
+		t.Errorf("TypeScript FilterFiles() = %v，期望a.ts/b.tsx/c.mts/d.vue", got)
+	}
+	if got := GetReviewProcess("javascript").FilterFiles(items); len(got) != 2 || got["a.ts"] != "" || got["d.vue"] != "" {
+		t.Errorf("JavaScript FilterFiles() = %v，期望e.js/f.cjs（.vue只归属TypeScript）", got)
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 复核结论
+const (
+	VerifyConfirmed  = "confirmed"  // 确认
+	VerifyDowngraded = "downgraded" // 降级
+	VerifyRejected   = "rejected"   // 驳回
+)
+
+// verifyPromptName 复核prompt模板名称
+const verifyPromptName = "verify"
+
+// verifyContextLines 复核时提供给模型的问题行上下文行数
+const verifyContextLines = 15
+
+// voteLineTolerance 多次采样投票时，同一文件中行号相差不超过该值、等级相同且规则或描述一致的问题视为同一问题
+const voteLineTolerance = 3
+
+// voteTextOverlap 多次采样投票时，没有共同规则ID/CWE编号的问题描述相似度（字符二元组重合比例）达到该值视为描述一致
+const voteTextOverlap = 0.5
+
+// verdictPattern 匹配复核结论：CONFIRM / DOWNGRADE:等级 / REJECT
+var verdictPattern = regexp.MustCompile(`(?i)(CONFIRM|DOWNGRADE|REJECT)(?:\s*[:：]\s*[\[【]?([^\]】\s]+)[\]】]?)?`)
+
+// verifyData 复核prompt渲染数据
+type verifyData struct {
+	Issue    BlockIssue
+	Code     string
+	Language string
+	Locale   string
+	Levels   struct{ Block, High, Medium, Suggest string }
+}
+
+// voteFindings 多次采样结果投票：同一问题在过半数采样中出现才保留，等级和描述以首次出现为准，置信度取各次采样的平均值
+func voteFindings(samples [][]BlockIssue) []BlockIssue {
+	type cluster struct {
+		issue      BlockIssue
+		line       int
+		votes      int
+		lastSample int
+		confidence []float64
+		members    []BlockIssue
+	}
+	var clusters []*cluster
+	for si, sample := range samples {
+		for _, issue := range sample {
+			line, _ := strconv.Atoi(issue.Line)
+			var matched *cluster
+			for _, c := range clusters {
+				if c.lastSample == si || c.issue.File != issue.File {
+					continue
+				}
+				if issue.File == "unknown" {
+					if normalizeSnippet(c.issue.Issue) == normalizeSnippet(issue.Issue) {
+						matched = c
+						break
+					}
+					continue
+				}
+				if abs(c.line-line) > voteLineTolerance {
+					continue
+				}
+				for _, member := range c.members {
+					if sameFinding(member, issue) {
+						matched = c
+						break
+					}
+				}
+				if matched != nil {
+					break
+				}
+			}
+			if matched == nil {
+				clusters = append(clusters, &cluster{issue: issue, line: line, votes: 1, lastSample: si, confidence: nonZero(issue.Confidence), members: []BlockIssue{issue}})
+				continue
+			}
+			matched.votes++
+			matched.lastSample = si
+			matched.confidence = append(matched.confidence, nonZero(issue.Confidence)...)
+			matched.members = append(matched.members, issue)
+		}
+	}
+
+	quorum := len(samples)/2 + 1
+	var issues []BlockIssue
+	for _, c := range clusters {
+		if c.votes < quorum {
+			logDebug("🗳️【voteFindings】%s:%s的问题仅在%d/%d次采样中出现，丢弃：%s\n", c.issue.File, c.issue.Line, c.votes, len(samples), c.issue.Issue)
+			continue
+		}
+		c.issue.Votes = fmt.Sprintf("%d/%d", c.votes, len(samples))
+		if len(c.confidence) > 0 {
+			sum := 0.0
+			for _, v := range c.confidence {
+				sum += v
+			}
+			c.issue.Confidence = sum / float64(len(c.confidence))
+		}
+		issues = append(issues, c.issue)
+	}
+	logDebug("🗳️【voteFindings】%d次采样共%d个候选问题，%d个获得多数票\n", len(samples), len(clusters), len(issues))
+	return issues
+}
+
+// sameFinding 判断两次采样中位置相近的问题是否为同一问题：等级相同，且规则ID/CWE编号一致或问题描述相似
+func sameFinding(a, b BlockIssue) bool {
+	if a.Level != b.Level {
+		return false
+	}
+	idA, idB := firstNonEmpty(a.RuleID, a.CWE), firstNonEmpty(b.RuleID, b.CWE)
+	if idA != "" && idB != "" {
+		return strings.EqualFold(idA, idB)
+	}
+	return issueTextOverlap(a.Issue, b.Issue) >= voteTextOverlap
+}
+
+// issueTextOverlap 计算两段问题描述的相似度：规范化后字符二元组的重合数占较短描述二元组数的比例
+func issueTextOverlap(a, b string) float64 {
+	gramsA, gramsB := bigrams(normalizeIssueText(a)), bigrams(normalizeIssueText(b))
+	if len(gramsA) == 0 || len(gramsB) == 0 {
+		if normalizeIssueText(a) == normalizeIssueText(b) {
+			return 1
+		}
+		return 0
+	}
+	shared := 0
+	for gram := range gramsA {
+		if gramsB[gram] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(min(len(gramsA), len(gramsB)))
+}
+
+// bigrams 文本中相邻两个字符组成的二元组集合
+func bigrams(text string) map[string]bool {
+	runes := []rune(text)
+	grams := make(map[string]bool)
+	for i := 0; i+1 < len(runes); i++ {
+		grams[string(runes[i:i+2])] = true
+	}
+	return grams
+}
+
+// verifyFindings 将block/high级问题连同相关代码交给模型复核，返回保留的问题和被驳回的问题；
+// 复核调用失败时保留原问题，基线中的存量问题、无法定位的问题、内置扫描（密钥、依赖）发现的问题和禁止发送给模型的文件中的问题不复核
+func verifyFindings(config Config, issues []BlockIssue, source *sourceReader) ([]BlockIssue, []BlockIssue) {
+	var kept, rejected []BlockIssue
+	for _, issue := range issues {
+		if (issue.Level != LevelBlock && issue.Level != LevelHigh) || issue.Known || issue.Scanner != "" || issue.File == "unknown" || redactor.Excluded(issue.File) {
+			kept = append(kept, issue)
+			continue
+		}
+
+		verdict, level, reason, err := verifyFinding(config, issue, source)
+		if err != nil {
+			fmt.Printf("⚠️【verifyFindings】复核%s:%s的问题失败，保留原结论：%s\n", issue.File, issue.Line, err)
+			kept = append(kept, issue)
+			continue
+		}
+		issue.Verification = verdict
+		issue.VerifyReason = reason
+		switch verdict {
+		case VerifyRejected:
+			logDebug("🧐【verifyFindings】驳回%s:%s的问题：%s（%s）\n", issue.File, issue.Line, issue.Issue, reason)
+			rejected = append(rejected, issue)
+			continue
+		case VerifyDowngraded:
+			if issue.RuleID != "" {
+				// 命中自定义规则的问题等级以规则配置为准，只允许确认或驳回
+				issue.Verification = VerifyConfirmed
+			} else {
+				logDebug("🧐【verifyFindings】%s:%s的问题由%s降级为%s（%s）\n", issue.File, issue.Line, issue.Level, level, reason)
+				issue.Level = level
+			}
+		}
+		kept = append(kept, issue)
+	}
+	return kept, rejected
+}
+
+// verifyFinding 复核单个问题，返回结论、降级后的等级和理由
+func verifyFinding(config Config, issue BlockIssue, source *sourceReader) (string, string, string, error) {
+	data := verifyData{
+		Issue:    issue,
+		Code:     codeContext(source, issue),
+		Language: languageDisplayName(config.Language),
+		Locale:   config.Locale,
+	}
+	data.Levels.Block, data.Levels.High, data.Levels.Medium, data.Levels.Suggest = LevelBlock, LevelHigh, LevelMedium, LevelSuggest
+	prompt, err := prompts.Execute(verifyPromptName, verifyPromptName, data)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	model := config.VerifyModel
+	if model == "" {
+		model = config.Model
+	}
+	record := AuditRecord{Model: model, Purpose: AuditPurposeVerify, Files: []AuditFile{{Path: issue.File, Bytes: len(data.Code)}}}
+	if err := auditModelCall(config, record, prompt); err != nil {
+		return "", "", "", err
+	}
+	content, err := callModel(config, model, prompt, verifyTemperature)
+	if err != nil {
+		return "", "", "", err
+	}
+	return parseVerdict(content, issue.Level)
+}
+
+// parseVerdict 解析复核结论；降级等级无效或不低于原等级时视为确认
+func parseVerdict(content, original string) (string, string, string, error) {
+	content = strings.TrimSpace(content)
+	firstLine, rest, _ := strings.Cut(content, "\n")
+	matches := verdictPattern.FindStringSubmatch(firstLine)
+	if matches == nil {
+		return "", "", "", fmt.Errorf("无法识别复核结论：%s", firstLine)
+	}
+
+	reason := strings.TrimSpace(rest)
+	for _, prefix := range []string{"理由：", "理由:", "Reason:", "reason:"} {
+		reason = strings.TrimSpace(strings.TrimPrefix(reason, prefix))
+	}
+
+	switch strings.ToUpper(matches[1]) {
+	case "REJECT":
+		return VerifyRejected, "", reason, nil
+	case "DOWNGRADE":
+		level, ok := normalizeLevel(matches[2])
+		if ok && levelRank(level) > levelRank(original) {
+			return VerifyDowngraded, level, reason, nil
+		}
+	}
+	return VerifyConfirmed, "", reason, nil
+}
+
+// codeContext 问题所在行前后的代码，格式为「行号| 代码」。整个文件脱敏后再截取：
+// 私钥等跨行的密钥只能从BEGIN标记开始识别，逐行或只对截取的片段脱敏会漏掉私钥正文
+func codeContext(source *sourceReader, issue BlockIssue) string {
+	lines := source.Lines(issue.File)
+	var all []int
+	for n := range lines {
+		all = append(all, n)
+	}
+	sort.Ints(all)
+	texts := make([]string, len(all))
+	for i, n := range all {
+		texts[i] = lines[n]
+	}
+	redacted := strings.Split(redactor.Redact(strings.Join(texts, "\n")), "\n")
+
+	center, _ := strconv.Atoi(issue.Line)
+	var b strings.Builder
+	for i, n := range all {
+		if n < center-verifyContextLines || n > center+verifyContextLines {
+			continue
+		}
+		text := texts[i]
+		if len(redacted) == len(all) {
+			text = redacted[i]
+		}
+		fmt.Fprintf(&b, "%d| %s\n", n, text)
+	}
+	if len(redacted) != len(all) {
+		// 自定义脱敏规则跨行匹配导致行数变化时，对截取的片段整体脱敏
+		return strings.TrimRight(redactor.Redact(b.String()), "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// abs 整数绝对值
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// nonZero 非零值包装为切片，零值返回nil
+func nonZero(v float64) []float64 {
+	if v == 0 {
+		return nil
+	}
+	return []float64{v}
+}
@@ -7,14 +7,14 @@
 
   # 编译macOS版本
   echo "编译macOS版本..."
-  GOOS=darwin GOARCH=arm64 go build -o bin/airvw_macos ./airvw/main.go
+  GOOS=darwin GOARCH=arm64 go build -o bin/airvw_macos ./airvw
 
   # 编译Windows版本
   echo "编译Windows版本..."
-  GOOS=windows GOARCH=amd64 go build -ldflags="-s -w" -o bin/airvw.exe ./airvw/main.go
+  GOOS=windows GOARCH=amd64 go build -ldflags="-s -w" -o bin/airvw.exe ./airvw
 
   # 编译Linux版本
   echo "编译Linux版本..."
-  GOOS=linux GOARCH=amd64 go build -ldflags="-s -w" -o bin/airvw-linux ./airvw/main.go
+  GOOS=linux GOARCH=amd64 go build -ldflags="-s -w" -o bin/airvw-linux ./airvw
 
   echo "编译完成！可执行文件位于bin目录中。"
\ No newline at end of file
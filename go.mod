@@ -2,9 +2,6 @@ module github.com/konglong87/airvw
 
 go 1.23.0
 
-require (
-	github.com/blinkbean/dingtalk v1.1.3
-	github.com/go-resty/resty/v2 v2.17.1
-)
+require github.com/go-resty/resty/v2 v2.17.1
 
 require golang.org/x/net v0.43.0 // indirect